@@ -3,6 +3,7 @@ package main
 import (
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/ca-risken/risken-mcp-server/pkg/logging"
 	"github.com/ca-risken/risken-mcp-server/pkg/riskenmcp"
@@ -15,7 +16,10 @@ const (
 )
 
 var (
-	httpPort string
+	httpPort             string
+	clientCacheTTL       time.Duration
+	clientCacheSize      int
+	invalidTokenCacheTTL time.Duration
 
 	httpCmd = &cobra.Command{
 		Use:   "http",
@@ -29,6 +33,9 @@ var (
 
 func init() {
 	httpCmd.Flags().StringVarP(&httpPort, "port", "p", "8080", "Port to listen on")
+	httpCmd.Flags().DurationVar(&clientCacheTTL, "client-cache-ttl", 5*time.Minute, "TTL of the validated RISKEN client cache per token")
+	httpCmd.Flags().IntVar(&clientCacheSize, "client-cache-size", 1000, "Maximum number of cached RISKEN clients")
+	httpCmd.Flags().DurationVar(&invalidTokenCacheTTL, "invalid-token-cache-ttl", 30*time.Second, "TTL of the invalid token cache")
 	rootCmd.AddCommand(httpCmd)
 }
 
@@ -44,12 +51,16 @@ func runHTTPServer() error {
 	url := os.Getenv("RISKEN_URL")
 
 	// Create MCP server
-	mcpserver := riskenmcp.NewServerForMultiProject(ServerName, ServerVersion, httpLogger)
+	mcpserver := riskenmcp.NewServerForMultiProject(ServerName, ServerVersion, newRISKENMCPConfig(), httpLogger)
 	httpServer := streamablehttp.NewAuthServer(
 		mcpserver.MCPServer,
 		url,
 		mcpEndpointPath,
 		httpLogger,
+		streamablehttp.WithClientCacheTTL(clientCacheTTL),
+		streamablehttp.WithClientCacheSize(clientCacheSize),
+		streamablehttp.WithInvalidTokenCacheTTL(invalidTokenCacheTTL),
+		streamablehttp.WithSubscriptionHandler(mcpserver.ServeSubscriptionHTTP),
 	)
 
 	addr := ":" + httpPort
@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/ca-risken/risken-mcp-server/pkg/riskenmcp"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +20,13 @@ var (
 	date    = "date"
 	debug   bool
 
+	findingFetchConcurrency int
+	projectCacheTTL         time.Duration
+	maxArchiveExpiryDays    int
+	fetchAllMaxFindings     int
+	fetchAllMaxBytes        int
+	resourcePollInterval    time.Duration
+
 	rootCmd = &cobra.Command{
 		Use:          "risken-mcp-server",
 		Short:        "RISKEN MCP Server",
@@ -29,6 +38,24 @@ var (
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Enable debug logging")
+	rootCmd.PersistentFlags().IntVar(&findingFetchConcurrency, "finding-fetch-concurrency", 5, "Maximum number of parallel finding lookups per request")
+	rootCmd.PersistentFlags().DurationVar(&projectCacheTTL, "project-cache-ttl", 5*time.Minute, "TTL of the cached signin and project lookup per client")
+	rootCmd.PersistentFlags().IntVar(&maxArchiveExpiryDays, "max-archive-expiry-days", 0, "Maximum days until archived findings expire (0: no limit)")
+	rootCmd.PersistentFlags().IntVar(&fetchAllMaxFindings, "fetch-all-max-findings", 500, "Maximum number of findings returned by search_finding with fetch_all")
+	rootCmd.PersistentFlags().IntVar(&fetchAllMaxBytes, "fetch-all-max-bytes", 256*1024, "Maximum JSON size in bytes of the findings returned by search_finding with fetch_all")
+	rootCmd.PersistentFlags().DurationVar(&resourcePollInterval, "resource-poll-interval", time.Minute, "Interval to poll the subscribed finding and alert resources for changes (minimum 10s)")
+}
+
+func newRISKENMCPConfig() *riskenmcp.Config {
+	return &riskenmcp.Config{
+		FindingFetchConcurrency: findingFetchConcurrency,
+		ProjectCacheTTL:         projectCacheTTL,
+		MaxArchiveExpiry:        time.Duration(maxArchiveExpiryDays) * 24 * time.Hour,
+		FetchAllMaxFindings:     fetchAllMaxFindings,
+		FetchAllMaxBytes:        fetchAllMaxBytes,
+		ResourcePollInterval:    resourcePollInterval,
+		SigningKey:              os.Getenv("MCP_SIGNING_KEY"),
+	}
 }
 
 func main() {
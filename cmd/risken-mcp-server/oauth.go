@@ -42,7 +42,7 @@ func runOAuthServer() error {
 	url := os.Getenv("RISKEN_URL")
 
 	// Create MCP server
-	mcpserver := riskenmcp.NewServerForMultiProject(ServerName, ServerVersion, oauthLogger)
+	mcpserver := riskenmcp.NewServerForMultiProject(ServerName, ServerVersion, newRISKENMCPConfig(), oauthLogger)
 	oauthServer := oauth.NewServer(
 		mcpserver.MCPServer,
 		&oauth.Config{
@@ -55,6 +55,7 @@ func runOAuthServer() error {
 		url,
 		mcpEndpointPath,
 		oauthLogger,
+		oauth.WithSubscriptionHandler(mcpserver.ServeSubscriptionHTTP),
 	)
 	if err := oauthServer.Initialize(context.Background()); err != nil {
 		return fmt.Errorf("failed to initialize OAuth server: %w", err)
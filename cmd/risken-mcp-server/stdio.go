@@ -8,7 +8,6 @@ import (
 	"github.com/ca-risken/go-risken"
 	"github.com/ca-risken/risken-mcp-server/pkg/logging"
 	"github.com/ca-risken/risken-mcp-server/pkg/riskenmcp"
-	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
 )
 
@@ -44,15 +43,15 @@ func runStdioServer() error {
 	}
 
 	// Create and start server
-	mcpserver := riskenmcp.NewServer(riskenClient, ServerName, ServerVersion, stdioLogger)
+	mcpserver := riskenmcp.NewServer(riskenClient, ServerName, ServerVersion, newRISKENMCPConfig(), stdioLogger)
 	stdioLogger.Info(
 		"Starting RISKEN MCP server...",
 		slog.String("name", ServerName),
 		slog.String("version", ServerVersion),
 	)
 
-	// ServeStdio handles signal handling and error management internally
-	return server.ServeStdio(mcpserver.MCPServer)
+	// ServeStdio handles signal handling, error management and resource subscriptions internally
+	return mcpserver.ServeStdio()
 }
 
 func newRISKENClient(url, token string) (*risken.Client, error) {
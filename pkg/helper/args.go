@@ -1,5 +1,10 @@
 package helper
 
+import (
+	"fmt"
+	"time"
+)
+
 func Pointer[T any](v T) *T {
 	return &v
 }
@@ -11,3 +16,30 @@ func ParseMCPArgs[T any](key string, mcpArgs map[string]any) (*T, error) {
 	}
 	return Pointer(value), nil
 }
+
+// ParseResourceURIArgs returns the value of the URI template variable.
+// mcp-go sets each URI template variable as []string.
+func ParseResourceURIArgs(key string, args map[string]any) (string, bool) {
+	switch v := args[key].(type) {
+	case []string:
+		if len(v) == 0 {
+			return "", false
+		}
+		return v[0], true
+	case string:
+		return v, true
+	default:
+		return "", false
+	}
+}
+
+// ParseISO8601Time parses the ISO8601 date or date-time string. e.g. 2025-01-01, 2025-01-01T00:00:00Z
+// A date without time zone is treated as UTC.
+func ParseISO8601Time(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid ISO8601 time: %s", value)
+}
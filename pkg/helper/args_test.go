@@ -2,6 +2,7 @@ package helper
 
 import (
 	"testing"
+	"time"
 )
 
 func TestParseMCPArgs(t *testing.T) {
@@ -130,3 +131,96 @@ func TestParseMCPArgs(t *testing.T) {
 	}
 
 }
+
+func TestParseResourceURIArgs(t *testing.T) {
+	cases := []struct {
+		name   string
+		key    string
+		args   map[string]any
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "string slice",
+			key:    "finding_id",
+			args:   map[string]any{"finding_id": []string{"123"}},
+			want:   "123",
+			wantOK: true,
+		},
+		{
+			name:   "string",
+			key:    "finding_id",
+			args:   map[string]any{"finding_id": "123"},
+			want:   "123",
+			wantOK: true,
+		},
+		{
+			name:   "empty slice",
+			key:    "finding_id",
+			args:   map[string]any{"finding_id": []string{}},
+			wantOK: false,
+		},
+		{
+			name:   "key not found",
+			key:    "finding_id",
+			args:   map[string]any{},
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := ParseResourceURIArgs(tc.key, tc.args)
+			if ok != tc.wantOK || got != tc.want {
+				t.Errorf("ParseResourceURIArgs() = %v, %v, want %v, %v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseISO8601Time(t *testing.T) {
+	cases := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "date",
+			value: "2025-12-31",
+			want:  time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "date time",
+			value: "2025-12-31T10:20:30Z",
+			want:  time.Date(2025, 12, 31, 10, 20, 30, 0, time.UTC),
+		},
+		{
+			name:  "date time with offset",
+			value: "2025-12-31T09:00:00+09:00",
+			want:  time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "date time without zone",
+			value: "2025-12-31T10:20:30",
+			want:  time.Date(2025, 12, 31, 10, 20, 30, 0, time.UTC),
+		},
+		{
+			name:    "invalid",
+			value:   "12/31/2025",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseISO8601Time(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseISO8601Time() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("ParseISO8601Time() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package helper
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TTLCache is a thread-safe in-memory cache whose entries expire after a TTL.
+type TTLCache[V any] struct {
+	mu      sync.Mutex
+	entries map[string]ttlCacheEntry[V]
+	ttl     time.Duration
+	maxSize int
+	now     func() time.Time
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type ttlCacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// NewTTLCache creates a cache holding at most maxSize entries for ttl each.
+// maxSize <= 0 means no size limit.
+func NewTTLCache[V any](ttl time.Duration, maxSize int) *TTLCache[V] {
+	return &TTLCache[V]{
+		entries: make(map[string]ttlCacheEntry[V]),
+		ttl:     ttl,
+		maxSize: maxSize,
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached value if it exists and has not expired.
+func (c *TTLCache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		c.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	c.hits.Add(1)
+	return entry.value, true
+}
+
+// Set stores the value with the default TTL.
+func (c *TTLCache[V]) Set(key string, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL stores the value with the given TTL.
+func (c *TTLCache[V]) SetWithTTL(key string, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; !exists && c.maxSize > 0 && len(c.entries) >= c.maxSize {
+		c.evict()
+	}
+	c.entries[key] = ttlCacheEntry[V]{
+		value:     value,
+		expiresAt: c.now().Add(ttl),
+	}
+}
+
+// Take returns the value and removes it from the cache, so that only one caller can take each entry.
+func (c *TTLCache[V]) Take(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	delete(c.entries, key)
+	if !ok || !c.now().Before(entry.expiresAt) {
+		c.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	c.hits.Add(1)
+	return entry.value, true
+}
+
+// Delete removes the entry from the cache.
+func (c *TTLCache[V]) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Len returns the number of entries including expired ones not yet removed.
+func (c *TTLCache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Stats returns the number of cache hits and misses of Get.
+func (c *TTLCache[V]) Stats() (hits, misses uint64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+// evict removes expired entries, or the entry closest to expiry if none has expired.
+// The caller must hold the lock.
+func (c *TTLCache[V]) evict() {
+	now := c.now()
+	oldestKey := ""
+	var oldest time.Time
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, k)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey = k
+			oldest = entry.expiresAt
+		}
+	}
+	if len(c.entries) >= c.maxSize && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
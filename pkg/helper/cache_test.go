@@ -0,0 +1,100 @@
+package helper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLCache(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewTTLCache[string](time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", "value-a")
+	if got, ok := c.Get("a"); !ok || got != "value-a" {
+		t.Errorf("Get(a) = %v, %v, want value-a, true", got, ok)
+	}
+
+	// expired
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get(a) after TTL should miss")
+	}
+
+	// custom TTL
+	c.SetWithTTL("b", "value-b", 10*time.Second)
+	now = now.Add(5 * time.Second)
+	if _, ok := c.Get("b"); !ok {
+		t.Error("Get(b) within TTL should hit")
+	}
+	now = now.Add(10 * time.Second)
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) after TTL should miss")
+	}
+
+	// delete
+	c.Set("c", "value-c")
+	c.Delete("c")
+	if _, ok := c.Get("c"); ok {
+		t.Error("Get(c) after Delete should miss")
+	}
+
+	hits, misses := c.Stats()
+	if hits != 2 || misses != 3 {
+		t.Errorf("Stats() = %d, %d, want 2, 3", hits, misses)
+	}
+}
+
+func TestTTLCacheTake(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewTTLCache[string](time.Minute, 0)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", "value-a")
+	if got, ok := c.Take("a"); !ok || got != "value-a" {
+		t.Errorf("Take(a) = %v, %v, want value-a, true", got, ok)
+	}
+	if _, ok := c.Take("a"); ok {
+		t.Error("Take(a) twice should miss")
+	}
+
+	// expired
+	c.Set("b", "value-b")
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Take("b"); ok {
+		t.Error("Take(b) after TTL should miss")
+	}
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len() = %d, want 0", got)
+	}
+}
+
+func TestTTLCacheMaxSize(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewTTLCache[int](time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	now = now.Add(time.Second)
+	c.Set("b", 2)
+	now = now.Add(time.Second)
+	c.Set("c", 3) // evicts "a", the entry closest to expiry
+
+	if got := c.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get(a) should be evicted")
+	}
+	for _, key := range []string{"b", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("Get(%s) should hit", key)
+		}
+	}
+
+	// overwrite an existing key does not evict
+	c.Set("b", 20)
+	if got, ok := c.Get("c"); !ok || got != 3 {
+		t.Errorf("Get(c) = %v, %v, want 3, true", got, ok)
+	}
+}
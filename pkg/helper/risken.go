@@ -2,7 +2,11 @@ package helper
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/ca-risken/go-risken"
 )
@@ -20,3 +24,18 @@ func CreateAndValidateRISKENClient(ctx context.Context, riskenURL, token string)
 	}
 	return client, nil
 }
+
+// HashToken returns the SHA-256 hex digest of the token to use it as a cache key without keeping the raw token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAuthError reports whether the error is an authentication or authorization error returned by RISKEN API.
+func IsAuthError(err error) bool {
+	var apiErr risken.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Status == http.StatusUnauthorized || apiErr.Status == http.StatusForbidden
+}
@@ -51,6 +51,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Add to context
 	ctx := riskenmcp.WithRISKENClient(r.Context(), riskenClient)
+	ctx = riskenmcp.WithRISKENClientKey(ctx, helper.HashToken(riskenToken))
 	r = r.WithContext(ctx)
 
 	// Log authenticated request
@@ -59,6 +60,11 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		slog.String("username", claims.Username),
 		slog.String("scope", claims.Scope))
 
+	// Handle resource subscriptions
+	if s.subscriptionHandler != nil && s.subscriptionHandler(w, r) {
+		return
+	}
+
 	// Delegate to MCP server
 	s.StreamableHTTPServer.ServeHTTP(w, r)
 }
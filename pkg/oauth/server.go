@@ -38,6 +38,19 @@ type Server struct {
 	oauth21Metadata *OAuth21Metadata
 	// Session manager for Third-Party Authorization Flow
 	sessionManager SessionManager
+	// Handler of the resource subscription requests, which the StreamableHTTPServer does not support
+	subscriptionHandler func(http.ResponseWriter, *http.Request) bool
+}
+
+// ServerOption configures the Server
+type ServerOption func(*Server)
+
+// WithSubscriptionHandler sets the handler of resources/subscribe and resources/unsubscribe requests.
+// The handler returns false for the other requests, which are delegated to the StreamableHTTPServer.
+func WithSubscriptionHandler(handler func(http.ResponseWriter, *http.Request) bool) ServerOption {
+	return func(s *Server) {
+		s.subscriptionHandler = handler
+	}
 }
 
 // NewServer creates MCP Resource Server with JWT validation
@@ -46,10 +59,11 @@ func NewServer(
 	oauthConfig *Config,
 	riskenURL, mcpEndpointPath string,
 	logger *slog.Logger,
+	opts ...ServerOption,
 ) *Server {
 	jwtValidator := NewJWTValidator(oauthConfig.MCPServerURL, logger)
 
-	return &Server{
+	s := &Server{
 		StreamableHTTPServer: server.NewStreamableHTTPServer(mcpServer, server.WithEndpointPath(mcpEndpointPath)),
 		config:               oauthConfig,
 		jwtValidator:         jwtValidator,
@@ -57,6 +71,10 @@ func NewServer(
 		mcpEndpointPath:      mcpEndpointPath,
 		logger:               logger,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Start starts the integrated server
@@ -3,7 +3,9 @@ package riskenmcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/ca-risken/core/proto/alert"
 	"github.com/ca-risken/go-risken"
@@ -12,6 +14,28 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+const (
+	defaultSearchAlertLimit = 50
+	maxSearchAlertLimit     = 200
+
+	// recentAlertLimit is the number of the alerts in the active alerts resources.
+	recentAlertLimit = 20
+)
+
+type SearchAlertResponse struct {
+	Alert      []*alert.Alert `json:"alert"`
+	Total      int            `json:"total"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// searchAlertQuery is the query of search_alert encoded in the cursor.
+// RISKEN ListAlert returns all the alerts, so the pages are sliced by alert ID in descending order.
+type searchAlertQuery struct {
+	Request  *alert.ListAlertRequest `json:"request"`
+	Limit    int                     `json:"limit"`
+	BeforeID uint32                  `json:"before_id,omitempty"`
+}
+
 func (s *Server) SearchAlert() (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("search_alert",
 			mcp.WithDescription("Search RISKEN alert. Use this when a request include \"alert\", \"アラート\" ..."),
@@ -21,6 +45,14 @@ func (s *Server) SearchAlert() (tool mcp.Tool, handler server.ToolHandlerFunc) {
 				mcp.Enum("1", "2", "3"),
 				mcp.DefaultNumber(1),
 			),
+			mcp.WithNumber(
+				"limit",
+				mcp.Description("Limit of the alerts. The alerts are listed in descending order of alert ID."),
+				mcp.DefaultNumber(defaultSearchAlertLimit),
+				mcp.Max(maxSearchAlertLimit),
+				mcp.Min(1),
+			),
+			withCursor(),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			riskenClient, err := s.GetRISKENClient(ctx)
@@ -29,17 +61,22 @@ func (s *Server) SearchAlert() (tool mcp.Tool, handler server.ToolHandlerFunc) {
 			}
 
 			// Parse params
-			params, err := s.ParseSearchAlertParams(ctx, req, riskenClient)
+			query, err := s.ParseSearchAlertParams(ctx, req, riskenClient)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
 			}
 
 			// Call RISKEN API
-			resp, err := riskenClient.ListAlert(ctx, params)
+			resp, err := riskenClient.ListAlert(ctx, query.Request)
 			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
 				return mcp.NewToolResultError(fmt.Sprintf("failed to search alert: %s", err)), nil
 			}
-			jsonData, err := json.Marshal(resp)
+			result, err := s.pageAlerts(query, resp.Alert)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to issue cursor: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(result)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
 			}
@@ -47,11 +84,20 @@ func (s *Server) SearchAlert() (tool mcp.Tool, handler server.ToolHandlerFunc) {
 		}
 }
 
-func (s *Server) ParseSearchAlertParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*alert.ListAlertRequest, error) {
+// ParseSearchAlertParams returns the query of the first page, or the query decoded from the cursor.
+func (s *Server) ParseSearchAlertParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*searchAlertQuery, error) {
 	p, err := s.GetCurrentProject(ctx, riskenClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project: %s", err)
 	}
+	query := &searchAlertQuery{}
+	ok, err := s.parseCursor(req, "search_alert", p.ProjectId, query)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return query, nil
+	}
 	param := &alert.ListAlertRequest{
 		ProjectId: p.ProjectId,
 		Status:    []alert.Status{alert.Status_ACTIVE},
@@ -64,6 +110,111 @@ func (s *Server) ParseSearchAlertParams(ctx context.Context, req mcp.CallToolReq
 	if status != nil {
 		param.Status = []alert.Status{alert.Status(int32(*status))}
 	}
+	query.Request = param
+	query.Limit = defaultSearchAlertLimit
+	limit, err := helper.ParseMCPArgs[float64]("limit", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("limit error: %s", err)
+	}
+	if limit != nil {
+		if *limit < 1 || *limit > maxSearchAlertLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxSearchAlertLimit)
+		}
+		query.Limit = int(*limit)
+	}
+	return query, nil
+}
+
+// pageAlerts returns the page of the alerts and the cursor of the next page.
+func (s *Server) pageAlerts(query *searchAlertQuery, alerts []*alert.Alert) (*SearchAlertResponse, error) {
+	if query.Limit <= 0 {
+		return nil, errors.New("invalid limit")
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].AlertId > alerts[j].AlertId })
+	start := 0
+	if query.BeforeID != 0 {
+		start = sort.Search(len(alerts), func(i int) bool { return alerts[i].AlertId < query.BeforeID })
+	}
+	end := min(start+query.Limit, len(alerts))
+	result := &SearchAlertResponse{
+		Alert: alerts[start:end],
+		Total: len(alerts),
+	}
+	if end < len(alerts) {
+		next := *query
+		next.BeforeID = alerts[end-1].AlertId
+		cursor, err := s.issueCursor("search_alert", query.Request.ProjectId, &next)
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = cursor
+	}
+	return result, nil
+}
+
+func (s *Server) GetActiveAlertsResource() (mcp.Resource, server.ResourceHandlerFunc) {
+	return mcp.NewResource(
+			"risken://alerts/active",
+			"RISKEN Active Alerts",
+			mcp.WithResourceDescription(fmt.Sprintf("The %d most recent active alerts of the project.", recentAlertLimit)),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.ActiveAlertsResourceContentsHandler(nil)
+}
+
+func (s *Server) GetActiveHighAlertsResource() (mcp.Resource, server.ResourceHandlerFunc) {
+	return mcp.NewResource(
+			"risken://alerts/active/high",
+			"RISKEN Active High Severity Alerts",
+			mcp.WithResourceDescription(fmt.Sprintf("The %d most recent active alerts of high severity in the project.", recentAlertLimit)),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.ActiveAlertsResourceContentsHandler([]string{"high"})
+}
 
-	return param, nil
+// ActiveAlertsResourceContentsHandler returns the most recent active alerts with the severities (all if empty).
+// The response has next_cursor of search_alert if there are more alerts.
+func (s *Server) ActiveAlertsResourceContentsHandler(severity []string) func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		riskenClient, err := s.GetRISKENClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+		}
+
+		p, err := s.GetCurrentProject(ctx, riskenClient)
+		if err != nil {
+			return nil, errors.New("failed to get project")
+		}
+		query := &searchAlertQuery{
+			Request: &alert.ListAlertRequest{
+				ProjectId: p.ProjectId,
+				Status:    []alert.Status{alert.Status_ACTIVE},
+				Severity:  severity,
+			},
+			Limit: recentAlertLimit,
+		}
+
+		// Call RISKEN API
+		resp, err := riskenClient.ListAlert(ctx, query.Request)
+		if err != nil {
+			s.invalidateOnAuthError(ctx, riskenClient, err)
+			return nil, errors.New("failed to list alerts")
+		}
+		result, err := s.pageAlerts(query, resp.Alert)
+		if err != nil {
+			return nil, errors.New("failed to issue cursor")
+		}
+		jsonData, err := json.Marshal(result)
+		if err != nil {
+			return nil, errors.New("failed to marshal alerts")
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		}, nil
+	}
 }
@@ -0,0 +1,453 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Actions of AlertConfigChange
+const (
+	alertConfigCreated = "created"
+	alertConfigUpdated = "updated"
+	alertConfigDeleted = "deleted"
+)
+
+// AlertConditionView is an alert condition with the IDs of the linked alert rules.
+type AlertConditionView struct {
+	AlertConditionID uint32   `json:"alert_condition_id"`
+	Description      string   `json:"description"`
+	Severity         string   `json:"severity"`
+	AndOr            string   `json:"and_or"`
+	Enabled          bool     `json:"enabled"`
+	RuleIDs          []uint32 `json:"rule_ids"`
+}
+
+// AlertConfigChange is the result of the mutation of alert conditions and rules.
+type AlertConfigChange struct {
+	Action string         `json:"action"`
+	Before any            `json:"before"`
+	After  any            `json:"after"`
+	Diff   []*FieldChange `json:"diff"`
+	// Error is the step that failed after the mutation. The change up to the step has been applied.
+	Error string `json:"error,omitempty"`
+}
+
+func newAlertConfigChange(action string, before, after any) *AlertConfigChange {
+	return &AlertConfigChange{
+		Action: action,
+		Before: before,
+		After:  after,
+		Diff:   diffFields(before, after),
+	}
+}
+
+func (s *Server) ListAlertConditions() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_alert_conditions",
+			mcp.WithDescription("List RISKEN alert conditions with the IDs of the linked alert rules. Use this when a request include \"alert condition\", \"アラート条件\"..."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+			p, err := s.GetCurrentProject(ctx, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			conditions, err := listAlertConditionViews(ctx, riskenClient, p.ProjectId)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list alert conditions: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(conditions)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) PutAlertCondition() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("put_alert_condition",
+			mcp.WithDescription("Create or update RISKEN alert condition. If alert_condition_id is specified, update the condition and the omitted fields keep the current values. Returns the before/after diff. Use this when a request include \"create alert condition\", \"update alert condition\", \"アラート条件を作成\", \"アラート条件を変更\"..."),
+			mcp.WithNumber(
+				"alert_condition_id",
+				mcp.Description("Alert condition ID to update. If not specified, a new condition is created."),
+			),
+			mcp.WithString(
+				"description",
+				mcp.Description("Description of the condition. It is also used as the alert description. (required for creation)"),
+			),
+			mcp.WithString(
+				"severity",
+				mcp.Description("Severity of the alert. (required for creation)"),
+				mcp.Enum("high", "medium", "low"),
+			),
+			mcp.WithString(
+				"and_or",
+				mcp.Description("How to combine the rules. and: all rules match, or: any rule matches. (default: or)"),
+				mcp.Enum("and", "or"),
+			),
+			mcp.WithBoolean(
+				"enabled",
+				mcp.Description("Enable the condition. (default: true)"),
+			),
+			mcp.WithArray(
+				"rule_ids",
+				mcp.Description("Alert rule IDs linked to the condition. If specified, the links are replaced with the rules."),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			before, params, ruleIDs, err := s.ParsePutAlertConditionParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			change, err := s.putAlertCondition(ctx, riskenClient, before, params, ruleIDs)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to put alert condition: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(change)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			if change.Error != "" {
+				// Do not retry as a creation, the condition already exists.
+				return mcp.NewToolResultError(fmt.Sprintf("the alert condition was %s, but failed to link the alert rules. Retry with alert_condition_id to fix the links: %s", change.Action, jsonData)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+// ParsePutAlertConditionParams returns the current condition (nil for creation), the request and the rule IDs to link (nil to keep).
+func (s *Server) ParsePutAlertConditionParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*AlertConditionView, *alert.PutAlertConditionRequest, []uint32, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	condition := &alert.AlertConditionForUpsert{
+		ProjectId: p.ProjectId,
+		AndOr:     "or",
+		Enabled:   true,
+	}
+
+	var before *AlertConditionView
+	id, err := helper.ParseMCPArgs[float64]("alert_condition_id", req.GetArguments())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("alert_condition_id error: %s", err)
+	}
+	if id != nil {
+		before, err = getAlertConditionView(ctx, riskenClient, p.ProjectId, uint32(*id))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		condition.AlertConditionId = before.AlertConditionID
+		condition.Description = before.Description
+		condition.Severity = before.Severity
+		condition.AndOr = before.AndOr
+		condition.Enabled = before.Enabled
+	}
+
+	description, err := helper.ParseMCPArgs[string]("description", req.GetArguments())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("description error: %s", err)
+	}
+	if description != nil {
+		condition.Description = *description
+	}
+	severity, err := helper.ParseMCPArgs[string]("severity", req.GetArguments())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("severity error: %s", err)
+	}
+	if severity != nil {
+		condition.Severity = *severity
+	}
+	andOr, err := helper.ParseMCPArgs[string]("and_or", req.GetArguments())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("and_or error: %s", err)
+	}
+	if andOr != nil {
+		condition.AndOr = *andOr
+	}
+	enabled, err := helper.ParseMCPArgs[bool]("enabled", req.GetArguments())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("enabled error: %s", err)
+	}
+	if enabled != nil {
+		condition.Enabled = *enabled
+	}
+	ruleIDs, err := parseUint32Array("rule_ids", req.GetArguments())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := condition.Validate(); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := validateAlertRuleIDs(ctx, riskenClient, p.ProjectId, ruleIDs); err != nil {
+		return nil, nil, nil, err
+	}
+	return before, &alert.PutAlertConditionRequest{
+		ProjectId:      p.ProjectId,
+		AlertCondition: condition,
+	}, ruleIDs, nil
+}
+
+func (s *Server) putAlertCondition(ctx context.Context, riskenClient *risken.Client, before *AlertConditionView, params *alert.PutAlertConditionRequest, ruleIDs []uint32) (*AlertConfigChange, error) {
+	resp, err := riskenClient.PutAlertCondition(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.AlertCondition == nil {
+		return nil, errors.New("empty response")
+	}
+	currentRuleIDs := []uint32{}
+	action := alertConfigCreated
+	if before != nil {
+		currentRuleIDs = before.RuleIDs
+		action = alertConfigUpdated
+	}
+	// The condition is already written, so a failure of linking is returned with the change instead of an error.
+	var linkErr error
+	if ruleIDs != nil {
+		currentRuleIDs, linkErr = linkAlertRules(ctx, riskenClient, params.ProjectId, resp.AlertCondition.AlertConditionId, currentRuleIDs, ruleIDs)
+	}
+
+	after := newAlertConditionView(resp.AlertCondition, currentRuleIDs)
+	s.logger.InfoContext(ctx, "Put alert condition",
+		slog.Any("project_id", params.ProjectId),
+		slog.Any("alert_condition_id", after.AlertConditionID),
+		slog.String("action", action))
+	change := newAlertConfigChange(action, before, after)
+	if linkErr != nil {
+		s.logger.WarnContext(ctx, "Failed to link alert rules",
+			slog.Any("project_id", params.ProjectId),
+			slog.Any("alert_condition_id", after.AlertConditionID),
+			slog.String("error", linkErr.Error()))
+		change.Error = linkErr.Error()
+	}
+	return change, nil
+}
+
+// validateAlertRuleIDs checks that the alert rules exist, so that the condition is not written with the links failing.
+func validateAlertRuleIDs(ctx context.Context, riskenClient *risken.Client, projectID uint32, ruleIDs []uint32) error {
+	if len(ruleIDs) == 0 {
+		return nil
+	}
+	rules, err := listAlertRuleViews(ctx, riskenClient, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to list alert rules: %s", err)
+	}
+	for _, id := range ruleIDs {
+		if !slices.ContainsFunc(rules, func(r *AlertRuleView) bool { return r.AlertRuleID == id }) {
+			return fmt.Errorf("alert rule not found: alert_rule_id=%d", id)
+		}
+	}
+	return nil
+}
+
+// linkAlertRules replaces the linked rules of the condition from current to want.
+// It returns the linked rule IDs, which are the ones up to the failed step on error.
+func linkAlertRules(ctx context.Context, riskenClient *risken.Client, projectID, alertConditionID uint32, current, want []uint32) ([]uint32, error) {
+	linked := slices.Clone(current)
+	for _, ruleID := range want {
+		if slices.Contains(current, ruleID) {
+			continue
+		}
+		if _, err := riskenClient.PutAlertCondRule(ctx, &alert.PutAlertCondRuleRequest{
+			ProjectId: projectID,
+			AlertCondRule: &alert.AlertCondRuleForUpsert{
+				ProjectId:        projectID,
+				AlertConditionId: alertConditionID,
+				AlertRuleId:      ruleID,
+			},
+		}); err != nil {
+			return linked, fmt.Errorf("failed to link alert rule(%d): %w", ruleID, err)
+		}
+		linked = append(linked, ruleID)
+	}
+	for _, ruleID := range current {
+		if slices.Contains(want, ruleID) {
+			continue
+		}
+		if err := riskenClient.DeleteAlertCondRule(ctx, &alert.DeleteAlertCondRuleRequest{
+			ProjectId:        projectID,
+			AlertConditionId: alertConditionID,
+			AlertRuleId:      ruleID,
+		}); err != nil {
+			return linked, fmt.Errorf("failed to unlink alert rule(%d): %w", ruleID, err)
+		}
+		linked = slices.DeleteFunc(linked, func(id uint32) bool { return id == ruleID })
+	}
+	return linked, nil
+}
+
+func (s *Server) DeleteAlertCondition() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("delete_alert_condition",
+			mcp.WithDescription("Delete RISKEN alert condition. The links to the alert rules and notifications are also deleted, but the rules themselves are kept. Use this when a request include \"delete alert condition\", \"アラート条件を削除\"..."),
+			mcp.WithNumber(
+				"alert_condition_id",
+				mcp.Description("Alert condition ID."),
+				mcp.Required(),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+			p, err := s.GetCurrentProject(ctx, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %s", err)), nil
+			}
+			id, err := helper.ParseMCPArgs[float64]("alert_condition_id", req.GetArguments())
+			if err != nil || id == nil {
+				return mcp.NewToolResultError("failed to parse params: alert_condition_id is required"), nil
+			}
+
+			// Call RISKEN API
+			before, err := getAlertConditionView(ctx, riskenClient, p.ProjectId, uint32(*id))
+			if err == nil {
+				err = riskenClient.DeleteAlertCondition(ctx, &alert.DeleteAlertConditionRequest{
+					ProjectId:        p.ProjectId,
+					AlertConditionId: before.AlertConditionID,
+				})
+			}
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to delete alert condition: %s", err)), nil
+			}
+			s.logger.InfoContext(ctx, "Deleted alert condition",
+				slog.Any("project_id", p.ProjectId),
+				slog.Any("alert_condition_id", before.AlertConditionID))
+			jsonData, err := json.Marshal(newAlertConfigChange(alertConfigDeleted, before, (*AlertConditionView)(nil)))
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) AnalyzeAlert() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("analyze_alert",
+			mcp.WithDescription("Re-analyze RISKEN alerts now with the current findings and alert conditions. Use this when a request include \"analyze alert\", \"re-evaluate alert\", \"アラートを再分析\"..."),
+			mcp.WithArray(
+				"alert_condition_id",
+				mcp.Description("Alert condition IDs to analyze. If not specified, all conditions in the project are analyzed."),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+			p, err := s.GetCurrentProject(ctx, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %s", err)), nil
+			}
+			ids, err := parseUint32Array("alert_condition_id", req.GetArguments())
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			if err := riskenClient.AnalyzeAlert(ctx, &alert.AnalyzeAlertRequest{
+				ProjectId:        p.ProjectId,
+				AlertConditionId: ids,
+			}); err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to analyze alert: %s", err)), nil
+			}
+			return mcp.NewToolResultText("Successfully analyzed alerts. Use search_alert to see the result."), nil
+		}
+}
+
+func newAlertConditionView(c *alert.AlertCondition, ruleIDs []uint32) *AlertConditionView {
+	if ruleIDs == nil {
+		ruleIDs = []uint32{}
+	}
+	return &AlertConditionView{
+		AlertConditionID: c.AlertConditionId,
+		Description:      c.Description,
+		Severity:         c.Severity,
+		AndOr:            c.AndOr,
+		Enabled:          c.Enabled,
+		RuleIDs:          ruleIDs,
+	}
+}
+
+func listAlertConditionViews(ctx context.Context, riskenClient *risken.Client, projectID uint32) ([]*AlertConditionView, error) {
+	conditions, err := riskenClient.ListAlertCondition(ctx, &alert.ListAlertConditionRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	condRules, err := riskenClient.ListAlertCondRule(ctx, &alert.ListAlertCondRuleRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ruleIDs := map[uint32][]uint32{}
+	for _, cr := range condRules.AlertCondRule {
+		ruleIDs[cr.AlertConditionId] = append(ruleIDs[cr.AlertConditionId], cr.AlertRuleId)
+	}
+	views := []*AlertConditionView{}
+	for _, c := range conditions.AlertCondition {
+		views = append(views, newAlertConditionView(c, ruleIDs[c.AlertConditionId]))
+	}
+	return views, nil
+}
+
+func getAlertConditionView(ctx context.Context, riskenClient *risken.Client, projectID, alertConditionID uint32) (*AlertConditionView, error) {
+	views, err := listAlertConditionViews(ctx, riskenClient, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if v.AlertConditionID == alertConditionID {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("alert condition not found: alert_condition_id=%d", alertConditionID)
+}
+
+// parseUint32Array parses the array of IDs. It returns nil if the key is not specified.
+func parseUint32Array(key string, args map[string]any) ([]uint32, error) {
+	values, err := helper.ParseMCPArgs[[]any](key, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %s", key, err)
+	}
+	if values == nil {
+		return nil, nil
+	}
+	ids := []uint32{}
+	for _, v := range *values {
+		id, ok := v.(float64)
+		if !ok || id < 0 {
+			return nil, fmt.Errorf("%s error: invalid ID: %v", key, v)
+		}
+		ids = append(ids, uint32(id))
+	}
+	return ids, nil
+}
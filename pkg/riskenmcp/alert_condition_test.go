@@ -0,0 +1,218 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestPutAlertCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		before     *AlertConditionView
+		ruleIDs    []uint32
+		failRuleID uint32
+		wantCalls  []string
+		wantAction string
+		wantDiff   []*FieldChange
+		wantError  string
+	}{
+		{
+			name:       "create without rules",
+			wantAction: alertConfigCreated,
+			wantDiff: []*FieldChange{
+				{Field: "alert_condition_id", Before: nil, After: uint32(2)},
+				{Field: "description", Before: nil, After: "critical findings"},
+				{Field: "severity", Before: nil, After: "high"},
+				{Field: "and_or", Before: nil, After: "or"},
+				{Field: "enabled", Before: nil, After: true},
+				{Field: "rule_ids", Before: nil, After: []uint32{}},
+			},
+		},
+		{
+			name:       "update and replace rules",
+			before:     &AlertConditionView{AlertConditionID: 2, Description: "critical findings", Severity: "medium", AndOr: "or", Enabled: true, RuleIDs: []uint32{20}},
+			ruleIDs:    []uint32{10},
+			wantCalls:  []string{"put 2-10", "delete 2-20"},
+			wantAction: alertConfigUpdated,
+			wantDiff: []*FieldChange{
+				{Field: "severity", Before: "medium", After: "high"},
+				{Field: "rule_ids", Before: []uint32{20}, After: []uint32{10}},
+			},
+		},
+		{
+			name:       "create with a failed link",
+			ruleIDs:    []uint32{10, 30},
+			failRuleID: 30,
+			wantCalls:  []string{"put 2-10"},
+			wantAction: alertConfigCreated,
+			wantDiff: []*FieldChange{
+				{Field: "alert_condition_id", Before: nil, After: uint32(2)},
+				{Field: "description", Before: nil, After: "critical findings"},
+				{Field: "severity", Before: nil, After: "high"},
+				{Field: "and_or", Before: nil, After: "or"},
+				{Field: "enabled", Before: nil, After: true},
+				{Field: "rule_ids", Before: nil, After: []uint32{10}},
+			},
+			wantError: "failed to link alert rule(30)",
+		},
+		{
+			name:       "update keeping rules",
+			before:     &AlertConditionView{AlertConditionID: 2, Description: "critical findings", Severity: "high", AndOr: "or", Enabled: true, RuleIDs: []uint32{20}},
+			wantAction: alertConfigUpdated,
+			wantDiff:   []*FieldChange{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := []string{}
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/v1/alert/put-condition":
+					_, _ = w.Write([]byte(`{"data":{"alert_condition":{"alert_condition_id":2,"description":"critical findings","severity":"high","and_or":"or","enabled":true}}}`))
+				case "/api/v1/alert/put-condition_rule":
+					req := &alert.PutAlertCondRuleRequest{}
+					_ = json.NewDecoder(r.Body).Decode(req)
+					if req.AlertCondRule.AlertRuleId == tt.failRuleID {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					mu.Lock()
+					calls = append(calls, fmt.Sprintf("put %d-%d", req.AlertCondRule.AlertConditionId, req.AlertCondRule.AlertRuleId))
+					mu.Unlock()
+					_, _ = w.Write([]byte(`{"data":{}}`))
+				case "/api/v1/alert/delete-condition_rule":
+					req := &alert.DeleteAlertCondRuleRequest{}
+					_ = json.NewDecoder(r.Body).Decode(req)
+					mu.Lock()
+					calls = append(calls, fmt.Sprintf("delete %d-%d", req.AlertConditionId, req.AlertRuleId))
+					mu.Unlock()
+					_, _ = w.Write([]byte(`{"data":{}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			s := newTestServer(client, nil)
+
+			params := &alert.PutAlertConditionRequest{
+				ProjectId:      1,
+				AlertCondition: &alert.AlertConditionForUpsert{ProjectId: 1, Description: "critical findings", Severity: "high", AndOr: "or", Enabled: true},
+			}
+			got, err := s.putAlertCondition(context.Background(), client, tt.before, params, tt.ruleIDs)
+			if err != nil {
+				t.Fatalf("putAlertCondition() error = %v", err)
+			}
+			if got.Action != tt.wantAction {
+				t.Errorf("putAlertCondition() action = %s, want %s", got.Action, tt.wantAction)
+			}
+			if diff := cmp.Diff(tt.wantDiff, got.Diff); diff != "" {
+				t.Errorf("putAlertCondition() diff mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantCalls, calls, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("cond rule calls mismatch (-want +got):\n%s", diff)
+			}
+			if !strings.HasPrefix(got.Error, tt.wantError) || (tt.wantError == "") != (got.Error == "") {
+				t.Errorf("putAlertCondition() error = %q, want %q", got.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateAlertRuleIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ruleIDs []uint32
+		wantErr bool
+	}{
+		{
+			name: "not specified",
+		},
+		{
+			name:    "existing rules",
+			ruleIDs: []uint32{10, 20},
+		},
+		{
+			name:    "unknown rule",
+			ruleIDs: []uint32{10, 30},
+			wantErr: true,
+		},
+	}
+
+	client := newTestRISKENClient(t, newTestAlertHandler())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAlertRuleIDs(context.Background(), client, 1, tt.ruleIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAlertRuleIDs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListAlertConditionViews(t *testing.T) {
+	client := newTestRISKENClient(t, newTestAlertHandler())
+
+	got, err := listAlertConditionViews(context.Background(), client, 1)
+	if err != nil {
+		t.Fatalf("listAlertConditionViews() error = %v", err)
+	}
+	want := []*AlertConditionView{
+		{AlertConditionID: 1, RuleIDs: []uint32{}},
+		{AlertConditionID: 2, Description: "critical findings", RuleIDs: []uint32{20}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("listAlertConditionViews() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := getAlertConditionView(context.Background(), client, 1, 999); err == nil {
+		t.Error("getAlertConditionView() error = nil, want not found error")
+	}
+}
+
+func TestParseUint32Array(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    []uint32
+		wantErr bool
+	}{
+		{
+			name: "not specified",
+			args: map[string]any{},
+			want: nil,
+		},
+		{
+			name: "ids",
+			args: map[string]any{"ids": []any{float64(1), float64(2)}},
+			want: []uint32{1, 2},
+		},
+		{
+			name:    "invalid id",
+			args:    map[string]any{"ids": []any{"a"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUint32Array("ids", tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseUint32Array() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseUint32Array() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	defaultAlertFindingLimit = 10
+	maxAlertFindingLimit     = 100
+)
+
+// alertStatuses is all statuses of alerts. ListAlert accepts only one status per request.
+var alertStatuses = []alert.Status{alert.Status_ACTIVE, alert.Status_PENDING, alert.Status_DEACTIVE}
+
+// AlertDetail is an alert with its history, related findings and the condition that triggered it.
+type AlertDetail struct {
+	Alert         *alert.Alert          `json:"alert"`
+	History       []*alert.AlertHistory `json:"history"`
+	Condition     *alert.AlertCondition `json:"condition,omitempty"`
+	Rules         []*alert.AlertRule    `json:"rules"`
+	Findings      []*finding.Finding    `json:"findings"`
+	FindingErrors []*FindingError       `json:"finding_errors,omitempty"`
+	TotalFindings int                   `json:"total_findings"`
+}
+
+func (s *Server) GetAlert() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_alert",
+			mcp.WithDescription("Get RISKEN alert with its history, related findings and the alert condition and rules that triggered it. Use this when a request include \"why did this alert fire\", \"alert detail\", \"アラートの詳細\"..."),
+			mcp.WithNumber(
+				"alert_id",
+				mcp.Description("Alert ID."),
+				mcp.Required(),
+			),
+			mcp.WithNumber(
+				"finding_limit",
+				mcp.Description("Limit of the related findings."),
+				mcp.DefaultNumber(defaultAlertFindingLimit),
+				mcp.Max(maxAlertFindingLimit),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			projectID, alertID, limit, err := s.ParseGetAlertParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			detail, err := s.getAlertDetail(ctx, riskenClient, projectID, alertID, limit)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get alert: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(detail)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal alert: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) GetAlertResource() (mcp.ResourceTemplate, server.ResourceTemplateHandlerFunc) {
+	return mcp.NewResourceTemplate(
+			"alert://{project_id}/{alert_id}",
+			"RISKEN Alert",
+		),
+		s.AlertResourceContentsHandler()
+}
+
+func (s *Server) AlertResourceContentsHandler() func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		riskenClient, err := s.GetRISKENClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+		}
+
+		p, err := s.getResourceProject(ctx, riskenClient, request)
+		if err != nil {
+			return nil, err
+		}
+		alertID, err := parseResourceID("alert_id", request)
+		if err != nil {
+			return nil, err
+		}
+
+		// Call RISKEN API
+		detail, err := s.getAlertDetail(ctx, riskenClient, p.ProjectId, uint32(alertID), defaultAlertFindingLimit)
+		if err != nil {
+			s.invalidateOnAuthError(ctx, riskenClient, err)
+			return nil, errors.New("failed to get alert")
+		}
+		jsonData, err := json.Marshal(detail)
+		if err != nil {
+			return nil, errors.New("failed to marshal alert")
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		}, nil
+	}
+}
+
+func (s *Server) ParseGetAlertParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (projectID, alertID uint32, findingLimit int, err error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to get project: %s", err)
+	}
+	id, err := helper.ParseMCPArgs[float64]("alert_id", req.GetArguments())
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("alert_id error: %s", err)
+	}
+	if id == nil {
+		return 0, 0, 0, errors.New("alert_id is required")
+	}
+	findingLimit = defaultAlertFindingLimit
+	limit, err := helper.ParseMCPArgs[float64]("finding_limit", req.GetArguments())
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("finding_limit error: %s", err)
+	}
+	if limit != nil {
+		findingLimit = min(max(int(*limit), 1), maxAlertFindingLimit)
+	}
+	return p.ProjectId, uint32(*id), findingLimit, nil
+}
+
+func (s *Server) getAlertDetail(ctx context.Context, riskenClient *risken.Client, projectID, alertID uint32, findingLimit int) (*AlertDetail, error) {
+	a, err := findAlert(ctx, riskenClient, projectID, alertID)
+	if err != nil {
+		return nil, err
+	}
+	detail := &AlertDetail{
+		Alert:    a,
+		History:  []*alert.AlertHistory{},
+		Rules:    []*alert.AlertRule{},
+		Findings: []*finding.Finding{},
+	}
+
+	history, err := riskenClient.ListAlertHistory(ctx, &alert.ListAlertHistoryRequest{
+		ProjectId: projectID,
+		AlertId:   alertID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert history: %w", err)
+	}
+	if history.AlertHistory != nil {
+		detail.History = history.AlertHistory
+	}
+
+	rels, err := riskenClient.ListRelAlertFinding(ctx, &alert.ListRelAlertFindingRequest{
+		ProjectId: projectID,
+		AlertId:   alertID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list related findings: %w", err)
+	}
+	findingIDs := []uint64{}
+	for _, rel := range rels.RelAlertFinding {
+		findingIDs = append(findingIDs, rel.FindingId)
+	}
+	detail.TotalFindings = len(findingIDs)
+	if len(findingIDs) > findingLimit {
+		findingIDs = findingIDs[:findingLimit]
+	}
+	detail.Findings, detail.FindingErrors = s.fetchFindings(ctx, riskenClient, projectID, findingIDs)
+
+	detail.Condition, detail.Rules, err = getAlertCondition(ctx, riskenClient, projectID, a.AlertConditionId)
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// findAlert looks up the alert in all statuses, because RISKEN has no API to get an alert by ID.
+func findAlert(ctx context.Context, riskenClient *risken.Client, projectID, alertID uint32) (*alert.Alert, error) {
+	return newAlertListCache().find(ctx, riskenClient, projectID, alertID)
+}
+
+// alertListCache keeps the alerts listed by project and status, so that many alerts can be looked up
+// with one ListAlert call for each project and status. e.g. the alerts subscribed by a session in a poll.
+// It is not safe for concurrent use.
+type alertListCache struct {
+	lists map[alertListKey][]*alert.Alert
+}
+
+type alertListKey struct {
+	riskenClient *risken.Client
+	projectID    uint32
+	status       alert.Status
+}
+
+func newAlertListCache() *alertListCache {
+	return &alertListCache{lists: map[alertListKey][]*alert.Alert{}}
+}
+
+// find looks up the alert in all statuses, listing the alerts of each status only once.
+func (c *alertListCache) find(ctx context.Context, riskenClient *risken.Client, projectID, alertID uint32) (*alert.Alert, error) {
+	for _, status := range alertStatuses {
+		key := alertListKey{riskenClient: riskenClient, projectID: projectID, status: status}
+		alerts, ok := c.lists[key]
+		if !ok {
+			resp, err := riskenClient.ListAlert(ctx, &alert.ListAlertRequest{
+				ProjectId: projectID,
+				Status:    []alert.Status{status},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list alerts: %w", err)
+			}
+			alerts = resp.Alert
+			c.lists[key] = alerts
+		}
+		for _, a := range alerts {
+			if a.AlertId == alertID {
+				return a, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("alert not found: alert_id=%d", alertID)
+}
+
+// getAlertCondition returns the alert condition and its rules. The condition is nil if it has been deleted.
+func getAlertCondition(ctx context.Context, riskenClient *risken.Client, projectID, alertConditionID uint32) (*alert.AlertCondition, []*alert.AlertRule, error) {
+	rules := []*alert.AlertRule{}
+	conditions, err := riskenClient.ListAlertCondition(ctx, &alert.ListAlertConditionRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list alert conditions: %w", err)
+	}
+	var condition *alert.AlertCondition
+	for _, c := range conditions.AlertCondition {
+		if c.AlertConditionId == alertConditionID {
+			condition = c
+			break
+		}
+	}
+	if condition == nil {
+		return nil, rules, nil
+	}
+
+	condRules, err := riskenClient.ListAlertCondRule(ctx, &alert.ListAlertCondRuleRequest{
+		ProjectId:        projectID,
+		AlertConditionId: alertConditionID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list alert condition rules: %w", err)
+	}
+	if len(condRules.AlertCondRule) == 0 {
+		return condition, rules, nil
+	}
+	ruleIDs := map[uint32]bool{}
+	for _, cr := range condRules.AlertCondRule {
+		ruleIDs[cr.AlertRuleId] = true
+	}
+	allRules, err := riskenClient.ListAlertRule(ctx, &alert.ListAlertRuleRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	for _, r := range allRules.AlertRule {
+		if ruleIDs[r.AlertRuleId] {
+			rules = append(rules, r)
+		}
+	}
+	return condition, rules, nil
+}
@@ -0,0 +1,70 @@
+package riskenmcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// newTestAlertHandler returns a fake RISKEN API handler for the alert APIs.
+// The alert 5 is deactive and has 3 related findings.
+func newTestAlertHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v1/alert/list-alert":
+			if q.Get("status") == "3" {
+				_, _ = w.Write([]byte(`{"data":{"alert":[{"alert_id":4,"alert_condition_id":1},{"alert_id":5,"alert_condition_id":2,"status":3}]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"alert":[{"alert_id":1,"alert_condition_id":1,"status":1}]}}`))
+		case "/api/v1/alert/list-history":
+			_, _ = w.Write([]byte(`{"data":{"alert_history":[{"alert_history_id":1,"alert_id":5,"history_type":"created"}]}}`))
+		case "/api/v1/alert/list-rel_alert_finding":
+			_, _ = w.Write([]byte(`{"data":{"rel_alert_finding":[{"alert_id":5,"finding_id":10},{"alert_id":5,"finding_id":11},{"alert_id":5,"finding_id":12}]}}`))
+		case "/api/v1/finding/get-finding":
+			_, _ = w.Write([]byte(`{"data":{"finding":{"finding_id":` + q.Get("finding_id") + `}}}`))
+		case "/api/v1/alert/list-condition":
+			_, _ = w.Write([]byte(`{"data":{"alert_condition":[{"alert_condition_id":1},{"alert_condition_id":2,"description":"critical findings"}]}}`))
+		case "/api/v1/alert/list-condition_rule":
+			_, _ = w.Write([]byte(`{"data":{"alert_cond_rule":[{"alert_condition_id":2,"alert_rule_id":20}]}}`))
+		case "/api/v1/alert/list-rule":
+			_, _ = w.Write([]byte(`{"data":{"alert_rule":[{"alert_rule_id":10},{"alert_rule_id":20,"name":"score >= 0.8","score":0.8}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestGetAlertDetail(t *testing.T) {
+	client := newTestRISKENClient(t, newTestAlertHandler())
+	s := newTestServer(client, nil)
+
+	got, err := s.getAlertDetail(context.Background(), client, 1, 5, 2)
+	if err != nil {
+		t.Fatalf("getAlertDetail() error = %v", err)
+	}
+	want := &AlertDetail{
+		Alert:         &alert.Alert{AlertId: 5, AlertConditionId: 2, Status: alert.Status_DEACTIVE},
+		History:       []*alert.AlertHistory{{AlertHistoryId: 1, AlertId: 5, HistoryType: "created"}},
+		Condition:     &alert.AlertCondition{AlertConditionId: 2, Description: "critical findings"},
+		Rules:         []*alert.AlertRule{{AlertRuleId: 20, Name: "score >= 0.8", Score: 0.8}},
+		Findings:      []*finding.Finding{{FindingId: 10}, {FindingId: 11}},
+		FindingErrors: []*FindingError{},
+		TotalFindings: 3,
+	}
+	opts := cmpopts.IgnoreUnexported(alert.Alert{}, alert.AlertHistory{}, alert.AlertCondition{}, alert.AlertRule{}, finding.Finding{})
+	if diff := cmp.Diff(want, got, opts); diff != "" {
+		t.Errorf("getAlertDetail() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := s.getAlertDetail(context.Background(), client, 1, 999, 10); err == nil {
+		t.Error("getAlertDetail() error = nil, want not found error")
+	}
+}
@@ -0,0 +1,415 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	notificationTypeSlack = "slack"
+	maskedValue           = "****"
+)
+
+// secretSettingKeys is the substrings of the notify setting keys whose values are masked.
+var secretSettingKeys = []string{"webhook", "token", "secret", "password", "api_key", "apikey"}
+
+// NotificationView is a notification setting with the secrets masked and the linked alert conditions.
+type NotificationView struct {
+	NotificationID uint32              `json:"notification_id"`
+	Name           string              `json:"name"`
+	Type           string              `json:"type"`
+	NotifySetting  map[string]any      `json:"notify_setting"`
+	Conditions     []*NotificationLink `json:"conditions"`
+
+	// rawNotifySetting is the notify setting without the masking, used to write back the unchanged keys.
+	rawNotifySetting map[string]any
+}
+
+// NotificationLink is an alert condition linked to the notification.
+type NotificationLink struct {
+	AlertConditionID uint32 `json:"alert_condition_id"`
+	CacheSecond      uint32 `json:"cache_second"`
+	NotifiedAt       string `json:"notified_at,omitempty"`
+}
+
+func (s *Server) ListNotifications() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_notifications",
+			mcp.WithDescription("List RISKEN alert notification settings (e.g. Slack webhook) with the secrets masked, and the alert conditions linked to each notification with the last notified time. "+
+				"Use this when a request include \"notification\", \"slack\", \"why didn't slack get pinged\", \"通知設定\", \"Slack通知\"..."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+			p, err := s.GetCurrentProject(ctx, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			notifications, err := listNotificationViews(ctx, riskenClient, p.ProjectId)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list notifications: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(notifications)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) PutNotification() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("put_notification",
+			mcp.WithDescription("Create or update RISKEN Slack notification setting. If notification_id is specified, update the setting and the omitted fields (including the webhook URL) keep the current values. Returns the before/after diff with the secrets masked. "+
+				"Use this when a request include \"create notification\", \"update slack webhook\", \"通知設定を作成\", \"通知設定を変更\"..."),
+			mcp.WithNumber(
+				"notification_id",
+				mcp.Description("Notification ID to update. If not specified, a new notification is created."),
+			),
+			mcp.WithString(
+				"name",
+				mcp.Description("Name of the notification. (required for creation)"),
+			),
+			mcp.WithString(
+				"webhook_url",
+				mcp.Description("Slack incoming webhook URL. Either webhook_url or channel_id is required for creation."),
+			),
+			mcp.WithString(
+				"channel_id",
+				mcp.Description("Slack channel ID to notify via the RISKEN Slack app. Either webhook_url or channel_id is required for creation."),
+			),
+			mcp.WithString(
+				"channel",
+				mcp.Description("Slack channel name to override the default channel of the webhook. ex) #security-alert"),
+			),
+			mcp.WithString(
+				"message",
+				mcp.Description("Additional message of the notification. ex) @here"),
+			),
+			mcp.WithString(
+				"locale",
+				mcp.Description("Language of the notification."),
+				mcp.Enum("ja", "en"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			before, params, err := s.ParsePutNotificationParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			change, err := s.putNotification(ctx, riskenClient, before, params)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to put notification: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(change)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+// ParsePutNotificationParams returns the current notification (nil for creation) and the request.
+func (s *Server) ParsePutNotificationParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*NotificationView, *alert.PutNotificationRequest, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	notification := &alert.NotificationForUpsert{
+		ProjectId: p.ProjectId,
+		Type:      notificationTypeSlack,
+	}
+	setting := map[string]any{}
+
+	var before *NotificationView
+	id, err := helper.ParseMCPArgs[float64]("notification_id", req.GetArguments())
+	if err != nil {
+		return nil, nil, fmt.Errorf("notification_id error: %s", err)
+	}
+	if id != nil {
+		before, err = getNotificationView(ctx, riskenClient, p.ProjectId, uint32(*id))
+		if err != nil {
+			return nil, nil, err
+		}
+		if before.Type != notificationTypeSlack {
+			return nil, nil, fmt.Errorf("unsupported notification type: %s", before.Type)
+		}
+		notification.NotificationId = before.NotificationID
+		notification.Name = before.Name
+		// NotifySetting of the view is masked, so copy the raw setting not to overwrite the secrets with the masked values.
+		for k, v := range before.rawNotifySetting {
+			setting[k] = v
+		}
+		// The webhook URL is masked by RISKEN. RISKEN keeps the current URL when it is empty.
+		delete(setting, "webhook_url")
+	}
+
+	name, err := helper.ParseMCPArgs[string]("name", req.GetArguments())
+	if err != nil {
+		return nil, nil, fmt.Errorf("name error: %s", err)
+	}
+	if name != nil {
+		notification.Name = *name
+	}
+	for _, key := range []string{"webhook_url", "channel_id", "locale"} {
+		v, err := helper.ParseMCPArgs[string](key, req.GetArguments())
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s error: %s", key, err)
+		}
+		if v != nil {
+			setting[key] = strings.TrimSpace(*v)
+		}
+	}
+	data, _ := setting["data"].(map[string]any)
+	if data == nil {
+		data = map[string]any{}
+	}
+	for _, key := range []string{"channel", "message"} {
+		v, err := helper.ParseMCPArgs[string](key, req.GetArguments())
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s error: %s", key, err)
+		}
+		if v != nil {
+			data[key] = *v
+		}
+	}
+	setting["data"] = data
+
+	notifySetting, err := json.Marshal(setting)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal notify setting: %s", err)
+	}
+	notification.NotifySetting = string(notifySetting)
+	if err := notification.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return before, &alert.PutNotificationRequest{
+		ProjectId:    p.ProjectId,
+		Notification: notification,
+	}, nil
+}
+
+func (s *Server) putNotification(ctx context.Context, riskenClient *risken.Client, before *NotificationView, params *alert.PutNotificationRequest) (*AlertConfigChange, error) {
+	resp, err := riskenClient.PutNotification(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Notification == nil || resp.Notification.NotificationId == 0 {
+		// RISKEN returns an empty response when the notification to update is not found.
+		return nil, errors.New("notification not found")
+	}
+	action := alertConfigCreated
+	conditions := []*NotificationLink{}
+	if before != nil {
+		action = alertConfigUpdated
+		conditions = before.Conditions
+	}
+	after, err := newNotificationView(resp.Notification, conditions)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Put notification",
+		slog.Any("project_id", params.ProjectId),
+		slog.Any("notification_id", after.NotificationID),
+		slog.String("action", action))
+	return newAlertConfigChange(action, before, after), nil
+}
+
+func (s *Server) TestNotification() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("test_notification",
+			mcp.WithDescription("Send a test message with RISKEN notification setting. Use this when a request include \"test notification\", \"test slack\", \"通知テスト\"..."),
+			mcp.WithNumber(
+				"notification_id",
+				mcp.Description("Notification ID."),
+				mcp.Required(),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+			p, err := s.GetCurrentProject(ctx, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %s", err)), nil
+			}
+			id, err := helper.ParseMCPArgs[float64]("notification_id", req.GetArguments())
+			if err != nil || id == nil {
+				return mcp.NewToolResultError("failed to parse params: notification_id is required"), nil
+			}
+
+			// Call RISKEN API
+			n, err := s.testNotification(ctx, riskenClient, p.ProjectId, uint32(*id))
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to test notification: %s", err)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Successfully sent a test message: notification_id=%d, name=%s", n.NotificationID, n.Name)), nil
+		}
+}
+
+func (s *Server) testNotification(ctx context.Context, riskenClient *risken.Client, projectID, notificationID uint32) (*NotificationView, error) {
+	// RISKEN returns no error when the notification is not found, so check it in advance.
+	n, err := getNotificationView(ctx, riskenClient, projectID, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	if n.Type != notificationTypeSlack {
+		return nil, fmt.Errorf("unsupported notification type: %s", n.Type)
+	}
+	if err := riskenClient.TestNotification(ctx, &alert.TestNotificationRequest{
+		ProjectId:      projectID,
+		NotificationId: notificationID,
+	}); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Sent test notification",
+		slog.Any("project_id", projectID),
+		slog.Any("notification_id", notificationID))
+	return n, nil
+}
+
+func listNotificationViews(ctx context.Context, riskenClient *risken.Client, projectID uint32) ([]*NotificationView, error) {
+	notifications, err := riskenClient.ListNotification(ctx, &alert.ListNotificationRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	condNotifications, err := riskenClient.ListAlertCondNotification(ctx, &alert.ListAlertCondNotificationRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	links := map[uint32][]*NotificationLink{}
+	for _, cn := range condNotifications.AlertCondNotification {
+		links[cn.NotificationId] = append(links[cn.NotificationId], &NotificationLink{
+			AlertConditionID: cn.AlertConditionId,
+			CacheSecond:      cn.CacheSecond,
+			NotifiedAt:       formatUnixTime(cn.NotifiedAt),
+		})
+	}
+	views := []*NotificationView{}
+	for _, n := range notifications.Notification {
+		conditions := links[n.NotificationId]
+		if conditions == nil {
+			conditions = []*NotificationLink{}
+		}
+		v, err := newNotificationView(n, conditions)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+	return views, nil
+}
+
+func getNotificationView(ctx context.Context, riskenClient *risken.Client, projectID, notificationID uint32) (*NotificationView, error) {
+	views, err := listNotificationViews(ctx, riskenClient, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if v.NotificationID == notificationID {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("notification not found: notification_id=%d", notificationID)
+}
+
+func newNotificationView(n *alert.Notification, conditions []*NotificationLink) (*NotificationView, error) {
+	setting, err := maskNotifySetting(n.NotifySetting)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notify setting: notification_id=%d, %w", n.NotificationId, err)
+	}
+	raw, err := parseNotifySetting(n.NotifySetting)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notify setting: notification_id=%d, %w", n.NotificationId, err)
+	}
+	return &NotificationView{
+		NotificationID:   n.NotificationId,
+		Name:             n.Name,
+		Type:             n.Type,
+		NotifySetting:    setting,
+		Conditions:       conditions,
+		rawNotifySetting: raw,
+	}, nil
+}
+
+// maskNotifySetting parses the notify setting JSON and masks the secret values.
+// RISKEN masks only the right half of the webhook URL, which still exposes the workspace ID of Slack.
+func maskNotifySetting(notifySetting string) (map[string]any, error) {
+	setting, err := parseNotifySetting(notifySetting)
+	if err != nil {
+		return nil, err
+	}
+	maskSecrets(setting)
+	return setting, nil
+}
+
+func parseNotifySetting(notifySetting string) (map[string]any, error) {
+	setting := map[string]any{}
+	if notifySetting == "" {
+		return setting, nil
+	}
+	if err := json.Unmarshal([]byte(notifySetting), &setting); err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+func maskSecrets(setting map[string]any) {
+	for k, v := range setting {
+		switch value := v.(type) {
+		case map[string]any:
+			maskSecrets(value)
+		case string:
+			if value != "" && isSecretSettingKey(k) {
+				setting[k] = maskSecret(value)
+			}
+		}
+	}
+}
+
+func isSecretSettingKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretSettingKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecret keeps only the scheme and host of URLs to tell which service is used.
+func maskSecret(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return maskedValue
+	}
+	return fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, maskedValue)
+}
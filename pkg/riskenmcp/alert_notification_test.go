@@ -0,0 +1,215 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newTestNotificationHandler(testCalled *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/alert/list-notification":
+			_, _ = w.Write([]byte(`{"data":{"notification":[` +
+				`{"notification_id":1,"name":"slack","type":"slack","notify_setting":"{\"webhook_url\":\"https://hooks.slack.com/services/T0000********\",\"data\":{\"channel\":\"#alert\"},\"locale\":\"ja\"}"},` +
+				`{"notification_id":2,"name":"other","type":"email","notify_setting":"{\"address\":\"sec@example.com\",\"api_key\":\"xxx\"}"}]}}`))
+		case "/api/v1/alert/list-condition_notification":
+			_, _ = w.Write([]byte(`{"data":{"alert_cond_notification":[{"alert_condition_id":2,"notification_id":1,"cache_second":1800,"notified_at":1700000000}]}}`))
+		case "/api/v1/alert/test-notification":
+			*testCalled++
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestListNotificationViews(t *testing.T) {
+	client := newTestRISKENClient(t, newTestNotificationHandler(new(int)))
+
+	got, err := listNotificationViews(context.Background(), client, 1)
+	if err != nil {
+		t.Fatalf("listNotificationViews() error = %v", err)
+	}
+	want := []*NotificationView{
+		{
+			NotificationID: 1,
+			Name:           "slack",
+			Type:           "slack",
+			NotifySetting: map[string]any{
+				"webhook_url": "https://hooks.slack.com/****",
+				"data":        map[string]any{"channel": "#alert"},
+				"locale":      "ja",
+			},
+			Conditions: []*NotificationLink{{AlertConditionID: 2, CacheSecond: 1800, NotifiedAt: "2023-11-14T22:13:20Z"}},
+		},
+		{
+			NotificationID: 2,
+			Name:           "other",
+			Type:           "email",
+			NotifySetting:  map[string]any{"address": "sec@example.com", "api_key": "****"},
+			Conditions:     []*NotificationLink{},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(NotificationView{})); diff != "" {
+		t.Errorf("listNotificationViews() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMaskNotifySetting(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  map[string]any{},
+		},
+		{
+			name:  "nested secrets",
+			input: `{"webhook_url":"https://hooks.slack.com/services/T/B/X","channel_id":"C01","data":{"token":"xoxb-1","message":"@here"}}`,
+			want: map[string]any{
+				"webhook_url": "https://hooks.slack.com/****",
+				"channel_id":  "C01",
+				"data":        map[string]any{"token": "****", "message": "@here"},
+			},
+		},
+		{
+			name:  "empty secret",
+			input: `{"webhook_url":"","password":"p@ss"}`,
+			want:  map[string]any{"webhook_url": "", "password": "****"},
+		},
+		{
+			name:    "invalid json",
+			input:   `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := maskNotifySetting(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("maskNotifySetting() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("maskNotifySetting() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTestNotification(t *testing.T) {
+	tests := []struct {
+		name           string
+		notificationID uint32
+		wantCalled     int
+		wantErr        bool
+	}{
+		{
+			name:           "slack",
+			notificationID: 1,
+			wantCalled:     1,
+		},
+		{
+			name:           "unsupported type",
+			notificationID: 2,
+			wantErr:        true,
+		},
+		{
+			name:           "not found",
+			notificationID: 999,
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := 0
+			client := newTestRISKENClient(t, newTestNotificationHandler(&called))
+			s := newTestServer(client, nil)
+
+			_, err := s.testNotification(context.Background(), client, 1, tt.notificationID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("testNotification() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("test-notification called = %d, want %d", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestParsePutNotificationParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        map[string]any
+		wantSetting map[string]any
+	}{
+		{
+			name: "keep the secrets",
+			args: map[string]any{"notification_id": float64(1), "name": "renamed"},
+			wantSetting: map[string]any{
+				"token":  "xoxb-secret",
+				"data":   map[string]any{"channel": "#alert", "api_key": "data-secret"},
+				"locale": "ja",
+			},
+		},
+		{
+			name: "update the webhook URL and the channel",
+			args: map[string]any{"notification_id": float64(1), "webhook_url": "https://hooks.slack.com/services/new", "channel": "#security"},
+			wantSetting: map[string]any{
+				"webhook_url": "https://hooks.slack.com/services/new",
+				"token":       "xoxb-secret",
+				"data":        map[string]any{"channel": "#security", "api_key": "data-secret"},
+				"locale":      "ja",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signinCount int32
+			projectHandler := newTestProjectHandler(&signinCount)
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/v1/alert/list-notification":
+					_, _ = w.Write([]byte(`{"data":{"notification":[{"notification_id":1,"name":"slack","type":"slack","notify_setting":` +
+						`"{\"webhook_url\":\"https://hooks.slack.com/services/T0000********\",\"token\":\"xoxb-secret\",\"data\":{\"channel\":\"#alert\",\"api_key\":\"data-secret\"},\"locale\":\"ja\"}"}]}}`))
+				case "/api/v1/alert/list-condition_notification":
+					_, _ = w.Write([]byte(`{"data":{}}`))
+				default:
+					projectHandler(w, r)
+				}
+			})
+			s := newTestServer(client, nil)
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			before, params, err := s.ParsePutNotificationParams(context.Background(), req, client)
+			if err != nil {
+				t.Fatalf("ParsePutNotificationParams() error = %v", err)
+			}
+			if before.NotifySetting["token"] != maskedValue {
+				t.Errorf("before.NotifySetting[token] = %v, want masked", before.NotifySetting["token"])
+			}
+			got := map[string]any{}
+			if err := json.Unmarshal([]byte(params.Notification.NotifySetting), &got); err != nil {
+				t.Fatalf("failed to unmarshal notify setting: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantSetting, got); diff != "" {
+				t.Errorf("ParsePutNotificationParams() notify setting mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
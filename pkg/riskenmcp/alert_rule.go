@@ -0,0 +1,292 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// AlertRuleView is an alert rule without timestamps.
+type AlertRuleView struct {
+	AlertRuleID  uint32  `json:"alert_rule_id"`
+	Name         string  `json:"name"`
+	Score        float32 `json:"score"`
+	ResourceName string  `json:"resource_name"`
+	Tag          string  `json:"tag"`
+	FindingCnt   uint32  `json:"finding_cnt"`
+}
+
+func (s *Server) ListAlertRules() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_alert_rules",
+			mcp.WithDescription("List RISKEN alert rules. Use this when a request include \"alert rule\", \"アラートルール\"..."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+			p, err := s.GetCurrentProject(ctx, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			rules, err := listAlertRuleViews(ctx, riskenClient, p.ProjectId)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list alert rules: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(rules)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) PutAlertRule() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("put_alert_rule",
+			mcp.WithDescription("Create or update RISKEN alert rule. A rule matches when the number of active findings that satisfy the score threshold, resource name pattern and tag is at least finding_cnt. "+
+				"If alert_rule_id is specified, update the rule and the omitted fields keep the current values. Returns the before/after diff. "+
+				"Use this when a request include \"create alert rule\", \"update alert rule\", \"アラートルールを作成\", \"アラートルールを変更\"..."),
+			mcp.WithNumber(
+				"alert_rule_id",
+				mcp.Description("Alert rule ID to update. If not specified, a new rule is created."),
+			),
+			mcp.WithString(
+				"name",
+				mcp.Description("Name of the rule. (required for creation)"),
+			),
+			mcp.WithNumber(
+				"score",
+				mcp.Description("Score threshold. Findings with the score greater than or equal to this value match. (default: 0.0)"),
+				mcp.Min(0),
+				mcp.Max(1),
+			),
+			mcp.WithString(
+				"resource_name",
+				mcp.Description("Resource name pattern. Findings whose resource name starts with this value match. Empty string matches all resources."),
+			),
+			mcp.WithString(
+				"tag",
+				mcp.Description("Finding tag. Findings with this tag match. Empty string matches all findings."),
+			),
+			mcp.WithNumber(
+				"finding_cnt",
+				mcp.Description("Minimum number of the matched findings. (default: 1)"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			before, params, err := s.ParsePutAlertRuleParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			change, err := s.putAlertRule(ctx, riskenClient, before, params)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to put alert rule: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(change)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+// ParsePutAlertRuleParams returns the current rule (nil for creation) and the request.
+func (s *Server) ParsePutAlertRuleParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*AlertRuleView, *alert.PutAlertRuleRequest, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	rule := &alert.AlertRuleForUpsert{
+		ProjectId:  p.ProjectId,
+		FindingCnt: 1,
+	}
+
+	var before *AlertRuleView
+	id, err := helper.ParseMCPArgs[float64]("alert_rule_id", req.GetArguments())
+	if err != nil {
+		return nil, nil, fmt.Errorf("alert_rule_id error: %s", err)
+	}
+	if id != nil {
+		before, err = getAlertRuleView(ctx, riskenClient, p.ProjectId, uint32(*id))
+		if err != nil {
+			return nil, nil, err
+		}
+		rule.AlertRuleId = before.AlertRuleID
+		rule.Name = before.Name
+		rule.Score = before.Score
+		rule.ResourceName = before.ResourceName
+		rule.Tag = before.Tag
+		rule.FindingCnt = before.FindingCnt
+	}
+
+	name, err := helper.ParseMCPArgs[string]("name", req.GetArguments())
+	if err != nil {
+		return nil, nil, fmt.Errorf("name error: %s", err)
+	}
+	if name != nil {
+		rule.Name = *name
+	}
+	score, err := helper.ParseMCPArgs[float64]("score", req.GetArguments())
+	if err != nil {
+		return nil, nil, fmt.Errorf("score error: %s", err)
+	}
+	if score != nil {
+		rule.Score = float32(*score)
+	}
+	resourceName, err := helper.ParseMCPArgs[string]("resource_name", req.GetArguments())
+	if err != nil {
+		return nil, nil, fmt.Errorf("resource_name error: %s", err)
+	}
+	if resourceName != nil {
+		rule.ResourceName = *resourceName
+	}
+	tag, err := helper.ParseMCPArgs[string]("tag", req.GetArguments())
+	if err != nil {
+		return nil, nil, fmt.Errorf("tag error: %s", err)
+	}
+	if tag != nil {
+		rule.Tag = *tag
+	}
+	findingCnt, err := helper.ParseMCPArgs[float64]("finding_cnt", req.GetArguments())
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding_cnt error: %s", err)
+	}
+	if findingCnt != nil {
+		if *findingCnt < 1 {
+			return nil, nil, errors.New("finding_cnt must be greater than or equal to 1")
+		}
+		rule.FindingCnt = uint32(*findingCnt)
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return before, &alert.PutAlertRuleRequest{
+		ProjectId: p.ProjectId,
+		AlertRule: rule,
+	}, nil
+}
+
+func (s *Server) putAlertRule(ctx context.Context, riskenClient *risken.Client, before *AlertRuleView, params *alert.PutAlertRuleRequest) (*AlertConfigChange, error) {
+	resp, err := riskenClient.PutAlertRule(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.AlertRule == nil {
+		return nil, errors.New("empty response")
+	}
+	action := alertConfigCreated
+	if before != nil {
+		action = alertConfigUpdated
+	}
+	after := newAlertRuleView(resp.AlertRule)
+	s.logger.InfoContext(ctx, "Put alert rule",
+		slog.Any("project_id", params.ProjectId),
+		slog.Any("alert_rule_id", after.AlertRuleID),
+		slog.String("action", action))
+	return newAlertConfigChange(action, before, after), nil
+}
+
+func (s *Server) DeleteAlertRule() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("delete_alert_rule",
+			mcp.WithDescription("Delete RISKEN alert rule. The links to the alert conditions are also deleted. Use this when a request include \"delete alert rule\", \"アラートルールを削除\"..."),
+			mcp.WithNumber(
+				"alert_rule_id",
+				mcp.Description("Alert rule ID."),
+				mcp.Required(),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+			p, err := s.GetCurrentProject(ctx, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %s", err)), nil
+			}
+			id, err := helper.ParseMCPArgs[float64]("alert_rule_id", req.GetArguments())
+			if err != nil || id == nil {
+				return mcp.NewToolResultError("failed to parse params: alert_rule_id is required"), nil
+			}
+
+			// Call RISKEN API
+			before, err := getAlertRuleView(ctx, riskenClient, p.ProjectId, uint32(*id))
+			if err == nil {
+				err = riskenClient.DeleteAlertRule(ctx, &alert.DeleteAlertRuleRequest{
+					ProjectId:   p.ProjectId,
+					AlertRuleId: before.AlertRuleID,
+				})
+			}
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to delete alert rule: %s", err)), nil
+			}
+			s.logger.InfoContext(ctx, "Deleted alert rule",
+				slog.Any("project_id", p.ProjectId),
+				slog.Any("alert_rule_id", before.AlertRuleID))
+			jsonData, err := json.Marshal(newAlertConfigChange(alertConfigDeleted, before, (*AlertRuleView)(nil)))
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func newAlertRuleView(r *alert.AlertRule) *AlertRuleView {
+	return &AlertRuleView{
+		AlertRuleID:  r.AlertRuleId,
+		Name:         r.Name,
+		Score:        r.Score,
+		ResourceName: r.ResourceName,
+		Tag:          r.Tag,
+		FindingCnt:   r.FindingCnt,
+	}
+}
+
+func listAlertRuleViews(ctx context.Context, riskenClient *risken.Client, projectID uint32) ([]*AlertRuleView, error) {
+	resp, err := riskenClient.ListAlertRule(ctx, &alert.ListAlertRuleRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	views := []*AlertRuleView{}
+	for _, r := range resp.AlertRule {
+		views = append(views, newAlertRuleView(r))
+	}
+	return views, nil
+}
+
+func getAlertRuleView(ctx context.Context, riskenClient *risken.Client, projectID, alertRuleID uint32) (*AlertRuleView, error) {
+	views, err := listAlertRuleViews(ctx, riskenClient, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if v.AlertRuleID == alertRuleID {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("alert rule not found: alert_rule_id=%d", alertRuleID)
+}
@@ -0,0 +1,241 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	simulatePageSize         = 200
+	maxSimulateFindings      = 2000
+	defaultSimulateFindings  = 10
+	maxSimulateMatchFindings = 100
+)
+
+// AlertConditionSimulation is the result of the alert condition simulation.
+type AlertConditionSimulation struct {
+	Matched       bool               `json:"matched"`
+	Count         int                `json:"count"`
+	FindingCnt    uint32             `json:"finding_cnt"`
+	Truncated     bool               `json:"truncated"`
+	Findings      []*finding.Finding `json:"findings"`
+	FindingErrors []*FindingError    `json:"finding_errors,omitempty"`
+}
+
+// simulateCondition is the proposed alert condition to simulate.
+type simulateCondition struct {
+	Score        float32
+	ResourceName string
+	Tag          string
+	FindingCnt   uint32
+}
+
+func (s *Server) SimulateAlertCondition() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("simulate_alert_condition",
+			mcp.WithDescription("Simulate a proposed RISKEN alert condition against the current active findings, and return the matched findings and count. "+
+				"It evaluates the condition in the same way as RISKEN alert rules, and does not create anything in RISKEN. "+
+				"Use this when a request include \"what would this alert match\", \"simulate alert\", \"dry run alert condition\", \"アラート条件を試す\"..."),
+			mcp.WithNumber(
+				"score",
+				mcp.Description("Score threshold. Findings with the score greater than or equal to this value match."),
+				mcp.DefaultNumber(0),
+				mcp.Min(0),
+				mcp.Max(1),
+			),
+			mcp.WithString(
+				"resource_name",
+				mcp.Description("Resource name pattern. Findings whose resource name starts with this value match."),
+			),
+			mcp.WithString(
+				"tag",
+				mcp.Description("Finding tag. Findings with this tag match. Empty string matches all findings."),
+			),
+			mcp.WithNumber(
+				"finding_cnt",
+				mcp.Description("Minimum number of the matched findings to raise an alert."),
+				mcp.DefaultNumber(1),
+				mcp.Min(1),
+			),
+			mcp.WithNumber(
+				"finding_limit",
+				mcp.Description("Limit of the matched findings in the response. The findings are ordered by score desc."),
+				mcp.DefaultNumber(defaultSimulateFindings),
+				mcp.Max(maxSimulateMatchFindings),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			projectID, cond, limit, err := s.ParseSimulateAlertConditionParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			result, err := s.simulateAlertCondition(ctx, riskenClient, projectID, cond, limit)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to simulate alert condition: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(result)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) ParseSimulateAlertConditionParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (uint32, *simulateCondition, int, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("failed to get project: %s", err)
+	}
+	cond := &simulateCondition{FindingCnt: 1}
+	score, err := helper.ParseMCPArgs[float64]("score", req.GetArguments())
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("score error: %s", err)
+	}
+	if score != nil {
+		if *score < 0 || *score > 1 {
+			return 0, nil, 0, errors.New("score must be between 0.0 and 1.0")
+		}
+		cond.Score = float32(*score)
+	}
+	resourceName, err := helper.ParseMCPArgs[string]("resource_name", req.GetArguments())
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("resource_name error: %s", err)
+	}
+	if resourceName != nil {
+		cond.ResourceName = *resourceName
+	}
+	tag, err := helper.ParseMCPArgs[string]("tag", req.GetArguments())
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("tag error: %s", err)
+	}
+	if tag != nil {
+		cond.Tag = *tag
+	}
+	findingCnt, err := helper.ParseMCPArgs[float64]("finding_cnt", req.GetArguments())
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("finding_cnt error: %s", err)
+	}
+	if findingCnt != nil {
+		if *findingCnt < 1 {
+			return 0, nil, 0, errors.New("finding_cnt must be greater than or equal to 1")
+		}
+		cond.FindingCnt = uint32(*findingCnt)
+	}
+	findingLimit := defaultSimulateFindings
+	limit, err := helper.ParseMCPArgs[float64]("finding_limit", req.GetArguments())
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("finding_limit error: %s", err)
+	}
+	if limit != nil {
+		findingLimit = min(max(int(*limit), 1), maxSimulateMatchFindings)
+	}
+	return p.ProjectId, cond, findingLimit, nil
+}
+
+// simulateAlertCondition evaluates the condition in the same way as RISKEN alert analysis:
+// active findings filtered by the score, resource name prefix and tag, and matched if the count reaches finding_cnt.
+// The IDs are deduplicated locally in case ListFinding returns the same finding on multiple pages.
+func (s *Server) simulateAlertCondition(ctx context.Context, riskenClient *risken.Client, projectID uint32, cond *simulateCondition, findingLimit int) (*AlertConditionSimulation, error) {
+	param := &finding.ListFindingRequest{
+		ProjectId: projectID,
+		FromScore: cond.Score,
+		Status:    finding.FindingStatus_FINDING_ACTIVE,
+		Sort:      "score",
+		Direction: "desc",
+		Limit:     simulatePageSize,
+	}
+	if cond.ResourceName != "" {
+		param.ResourceName = []string{cond.ResourceName}
+	}
+	if cond.Tag != "" {
+		param.Tag = []string{cond.Tag}
+	}
+
+	result := &AlertConditionSimulation{FindingCnt: cond.FindingCnt}
+	seen := map[uint64]bool{}
+	findingIDs := []uint64{}
+	var total uint32
+	for param.Offset = 0; ; param.Offset += simulatePageSize {
+		resp, err := riskenClient.ListFinding(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		total = resp.Total
+		for _, id := range resp.FindingId {
+			if !seen[id] {
+				seen[id] = true
+				findingIDs = append(findingIDs, id)
+			}
+		}
+		if len(resp.FindingId) < simulatePageSize || int(param.Offset)+simulatePageSize >= int(resp.Total) {
+			break
+		}
+		if int(param.Offset)+simulatePageSize >= maxSimulateFindings {
+			result.Truncated = true
+			break
+		}
+	}
+	result.Count = len(findingIDs)
+	if result.Truncated {
+		// The total of ListFinding may include the duplicated IDs, but it is the best estimate.
+		result.Count = int(total)
+	}
+	result.Matched = result.Count >= int(cond.FindingCnt)
+
+	if len(findingIDs) > findingLimit {
+		findingIDs = findingIDs[:findingLimit]
+	}
+	findings, findingErrors := s.fetchFindings(ctx, riskenClient, projectID, findingIDs)
+	matched := make([]bool, len(findings))
+	errs := make([]error, len(findings))
+	s.runParallel(len(findings), func(i int) {
+		matched[i], errs[i] = matchSimulateCondition(ctx, riskenClient, projectID, cond, findings[i])
+	})
+	result.Findings = []*finding.Finding{}
+	for i, f := range findings {
+		if errs[i] != nil {
+			findingErrors = append(findingErrors, &FindingError{FindingID: f.FindingId, Error: errs[i].Error()})
+			continue
+		}
+		if matched[i] {
+			result.Findings = append(result.Findings, f)
+		}
+	}
+	if len(findingErrors) > 0 {
+		result.FindingErrors = findingErrors
+	}
+	return result, nil
+}
+
+// matchSimulateCondition checks the score, resource name and tag of the fetched finding, which may be updated after listing.
+func matchSimulateCondition(ctx context.Context, riskenClient *risken.Client, projectID uint32, cond *simulateCondition, f *finding.Finding) (bool, error) {
+	if f.Score < cond.Score || !strings.HasPrefix(f.ResourceName, cond.ResourceName) {
+		return false, nil
+	}
+	if cond.Tag == "" {
+		return true, nil
+	}
+	t, err := findFindingTag(ctx, riskenClient, projectID, f.FindingId, cond.Tag)
+	if err != nil {
+		return false, err
+	}
+	return t != nil, nil
+}
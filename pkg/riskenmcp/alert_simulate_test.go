@@ -0,0 +1,122 @@
+package riskenmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSimulateAlertCondition(t *testing.T) {
+	tests := []struct {
+		name          string
+		cond          *simulateCondition
+		listIDs       []uint64
+		wantQuery     map[string]string
+		wantMatched   bool
+		wantCount     int
+		wantFindings  []uint64
+		wantTruncated bool
+	}{
+		{
+			name:         "matched",
+			cond:         &simulateCondition{Score: 0.8, ResourceName: "arn:aws:s3", FindingCnt: 2},
+			listIDs:      []uint64{1, 2, 3},
+			wantQuery:    map[string]string{"from_score": "0.8", "resource_name": "arn:aws:s3", "status": "1"},
+			wantMatched:  true,
+			wantCount:    3,
+			wantFindings: []uint64{1, 2},
+		},
+		{
+			name:         "duplicated ids",
+			cond:         &simulateCondition{FindingCnt: 3},
+			listIDs:      []uint64{1, 1, 2},
+			wantMatched:  false,
+			wantCount:    2,
+			wantFindings: []uint64{1, 2},
+		},
+		{
+			name:         "tag",
+			cond:         &simulateCondition{Tag: "prod", FindingCnt: 3},
+			listIDs:      []uint64{1, 2, 3},
+			wantQuery:    map[string]string{"tag": "prod"},
+			wantMatched:  true,
+			wantCount:    3,
+			wantFindings: []uint64{1}, // the tag of finding 2 was removed after listing
+		},
+		{
+			name:          "truncated",
+			cond:          &simulateCondition{FindingCnt: 1},
+			listIDs:       make([]uint64, maxSimulateFindings+1),
+			wantMatched:   true,
+			wantCount:     maxSimulateFindings + 1,
+			wantFindings:  []uint64{1, 2},
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.listIDs {
+				if tt.listIDs[i] == 0 {
+					tt.listIDs[i] = uint64(i + 1)
+				}
+			}
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method != http.MethodGet {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				q := r.URL.Query()
+				switch r.URL.Path {
+				case "/api/v1/finding/list-finding":
+					for k, v := range tt.wantQuery {
+						if q.Get(k) != v {
+							t.Errorf("query %s = %s, want %s", k, q.Get(k), v)
+						}
+					}
+					offset, _ := strconv.Atoi(q.Get("offset"))
+					end := min(offset+simulatePageSize, len(tt.listIDs))
+					ids := []string{}
+					for _, id := range tt.listIDs[offset:end] {
+						ids = append(ids, strconv.FormatUint(id, 10))
+					}
+					_, _ = fmt.Fprintf(w, `{"data":{"finding_id":[%s],"count":%d,"total":%d}}`, strings.Join(ids, ","), len(ids), len(tt.listIDs))
+				case "/api/v1/finding/list-finding-tag":
+					if q.Get("finding_id") == "2" {
+						_, _ = w.Write([]byte(`{"data":{"tag":[{"finding_tag_id":1,"finding_id":2,"tag":"dev"}],"count":1,"total":1}}`))
+						return
+					}
+					_, _ = fmt.Fprintf(w, `{"data":{"tag":[{"finding_tag_id":1,"finding_id":%s,"tag":"prod"}],"count":1,"total":1}}`, q.Get("finding_id"))
+				case "/api/v1/finding/get-finding":
+					_, _ = fmt.Fprintf(w, `{"data":{"finding":{"finding_id":%s,"score":0.9,"resource_name":"arn:aws:s3:::bucket"}}}`, q.Get("finding_id"))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			s := newTestServer(client, nil)
+
+			got, err := s.simulateAlertCondition(context.Background(), client, 1, tt.cond, 2)
+			if err != nil {
+				t.Fatalf("simulateAlertCondition() error = %v", err)
+			}
+			if got.Matched != tt.wantMatched || got.Count != tt.wantCount || got.Truncated != tt.wantTruncated {
+				t.Errorf("simulateAlertCondition() = matched:%v count:%d truncated:%v, want matched:%v count:%d truncated:%v",
+					got.Matched, got.Count, got.Truncated, tt.wantMatched, tt.wantCount, tt.wantTruncated)
+			}
+			gotIDs := []uint64{}
+			for _, f := range got.Findings {
+				gotIDs = append(gotIDs, f.FindingId)
+			}
+			if diff := cmp.Diff(tt.wantFindings, gotIDs); diff != "" {
+				t.Errorf("simulateAlertCondition() findings mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// UpdateAlertStatusResponse is the result of the alert status update.
+type UpdateAlertStatusResponse struct {
+	Alert          *alert.Alert `json:"alert"`
+	PreviousStatus string       `json:"previous_status"`
+	Comment        string       `json:"comment,omitempty"`
+}
+
+func (s *Server) PendAlert() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return s.updateAlertStatusTool(
+		"pend_alert",
+		"Change the status of RISKEN alert to pending. Use this when a request include \"pend alert\", \"アラートを保留\"...",
+		alert.Status_PENDING,
+	)
+}
+
+func (s *Server) DeactivateAlert() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return s.updateAlertStatusTool(
+		"deactivate_alert",
+		"Change the status of RISKEN alert to deactive (resolved). Use this when a request include \"close alert\", \"resolve alert\", \"アラートを解決\", \"アラートをクローズ\"...",
+		alert.Status_DEACTIVE,
+	)
+}
+
+func (s *Server) updateAlertStatusTool(name, description string, status alert.Status) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool(name,
+			mcp.WithDescription(description),
+			mcp.WithNumber(
+				"alert_id",
+				mcp.Description("Alert ID."),
+				mcp.Required(),
+			),
+			mcp.WithString(
+				"comment",
+				mcp.Description("Reason of the status change. ex) The findings were fixed in PR #123. "+
+					"RISKEN alert has no comment field, so the comment is only recorded in the MCP server log and is not saved in RISKEN."),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			projectID, alertID, comment, err := s.ParseUpdateAlertStatusParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			resp, err := s.updateAlertStatus(ctx, riskenClient, projectID, alertID, status, comment)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to update alert status: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(resp)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) ParseUpdateAlertStatusParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (projectID, alertID uint32, comment string, err error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("failed to get project: %s", err)
+	}
+	id, err := helper.ParseMCPArgs[float64]("alert_id", req.GetArguments())
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("alert_id error: %s", err)
+	}
+	if id == nil {
+		return 0, 0, "", errors.New("alert_id is required")
+	}
+	c, err := helper.ParseMCPArgs[string]("comment", req.GetArguments())
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("comment error: %s", err)
+	}
+	if c != nil {
+		comment = strings.TrimSpace(*c)
+	}
+	return p.ProjectId, uint32(*id), comment, nil
+}
+
+// updateAlertStatus changes the alert status keeping the other fields.
+// RISKEN alert API has no comment field, so the comment is only recorded in the server log.
+func (s *Server) updateAlertStatus(ctx context.Context, riskenClient *risken.Client, projectID, alertID uint32, status alert.Status, comment string) (*UpdateAlertStatusResponse, error) {
+	a, err := findAlert(ctx, riskenClient, projectID, alertID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == status {
+		return nil, fmt.Errorf("alert is already %s: alert_id=%d", alertStatusName(status), alertID)
+	}
+	resp, err := riskenClient.PutAlert(ctx, &alert.PutAlertRequest{
+		ProjectId: projectID,
+		Alert: &alert.AlertForUpsert{
+			AlertId:          a.AlertId,
+			AlertConditionId: a.AlertConditionId,
+			Description:      a.Description,
+			Severity:         a.Severity,
+			ProjectId:        projectID,
+			Status:           status,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Updated alert status",
+		slog.Any("project_id", projectID),
+		slog.Any("alert_id", alertID),
+		slog.String("from", alertStatusName(a.Status)),
+		slog.String("to", alertStatusName(status)),
+		slog.String("comment", comment))
+	return &UpdateAlertStatusResponse{
+		Alert:          resp.Alert,
+		PreviousStatus: alertStatusName(a.Status),
+		Comment:        comment,
+	}, nil
+}
+
+// alertStatusName returns the status name used in the tool descriptions. e.g. active, pending, deactive
+func alertStatusName(status alert.Status) string {
+	switch status {
+	case alert.Status_ACTIVE:
+		return "active"
+	case alert.Status_PENDING:
+		return "pending"
+	case alert.Status_DEACTIVE:
+		return "deactive"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,116 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestUpdateAlertStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		alertID uint32
+		status  alert.Status
+		want    *alert.AlertForUpsert
+		wantErr bool
+	}{
+		{
+			name:    "pend active alert",
+			alertID: 1,
+			status:  alert.Status_PENDING,
+			want:    &alert.AlertForUpsert{AlertId: 1, AlertConditionId: 1, ProjectId: 1, Status: alert.Status_PENDING},
+		},
+		{
+			name:    "already deactive",
+			alertID: 5,
+			status:  alert.Status_DEACTIVE,
+			wantErr: true,
+		},
+		{
+			name:    "not found",
+			alertID: 999,
+			status:  alert.Status_DEACTIVE,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *alert.AlertForUpsert
+			alertHandler := newTestAlertHandler()
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/alert/put-alert" {
+					alertHandler(w, r)
+					return
+				}
+				req := &alert.PutAlertRequest{}
+				_ = json.NewDecoder(r.Body).Decode(req)
+				got = req.Alert
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data":{"alert":{"alert_id":1,"status":2}}}`))
+			})
+			s := newTestServer(client, nil)
+
+			resp, err := s.updateAlertStatus(context.Background(), client, 1, tt.alertID, tt.status, "resolved")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("updateAlertStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(alert.AlertForUpsert{})); diff != "" {
+				t.Errorf("PutAlert request mismatch (-want +got):\n%s", diff)
+			}
+			if !tt.wantErr && (resp.PreviousStatus != "active" || resp.Comment != "resolved") {
+				t.Errorf("updateAlertStatus() = %+v", resp)
+			}
+		})
+	}
+}
+
+func TestParseUpdateAlertStatusParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        map[string]any
+		wantAlertID uint32
+		wantComment string
+		wantErr     bool
+	}{
+		{
+			name:        "with comment",
+			args:        map[string]any{"alert_id": float64(1), "comment": " fixed in PR #123 "},
+			wantAlertID: 1,
+			wantComment: "fixed in PR #123",
+		},
+		{
+			name:        "without comment",
+			args:        map[string]any{"alert_id": float64(1)},
+			wantAlertID: 1,
+		},
+		{
+			name:    "no alert_id",
+			args:    map[string]any{"comment": "fixed"},
+			wantErr: true,
+		},
+	}
+
+	var signinCount int32
+	client := newTestRISKENClient(t, newTestProjectHandler(&signinCount))
+	s := newTestServer(client, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			_, alertID, comment, err := s.ParseUpdateAlertStatusParams(context.Background(), req, client)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUpdateAlertStatusParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if alertID != tt.wantAlertID || comment != tt.wantComment {
+				t.Errorf("ParseUpdateAlertStatusParams() = %d, %q, want %d, %q", alertID, comment, tt.wantAlertID, tt.wantComment)
+			}
+		})
+	}
+}
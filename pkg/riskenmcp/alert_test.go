@@ -0,0 +1,83 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestActiveAlertsResourceContentsHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		severity      []string
+		wantSeverity  string
+		wantIDs       []uint32
+		wantNext      bool
+		alertsPerPage int
+	}{
+		{
+			name:          "all severities",
+			wantIDs:       []uint32{25, 24, 23, 22, 21, 20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6},
+			wantNext:      true,
+			alertsPerPage: 25,
+		},
+		{
+			name:          "high",
+			severity:      []string{"high"},
+			wantSeverity:  "high",
+			wantIDs:       []uint32{2, 1},
+			alertsPerPage: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signinCount int32
+			projectHandler := newTestProjectHandler(&signinCount)
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/alert/list-alert" {
+					projectHandler(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				q := r.URL.Query()
+				if q.Get("status") != "1" || q.Get("severity") != tt.wantSeverity {
+					t.Errorf("list-alert query = %s, want status=1 severity=%s", r.URL.RawQuery, tt.wantSeverity)
+				}
+				alerts := []string{}
+				for id := 1; id <= tt.alertsPerPage; id++ {
+					alerts = append(alerts, fmt.Sprintf(`{"alert_id":%d,"status":1}`, id))
+				}
+				_, _ = fmt.Fprintf(w, `{"data":{"alert":[%s]}}`, strings.Join(alerts, ","))
+			})
+			s := newTestServer(client, nil)
+
+			req := mcp.ReadResourceRequest{}
+			req.Params.URI = "risken://alerts/active"
+			contents, err := s.ActiveAlertsResourceContentsHandler(tt.severity)(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handler() error = %v", err)
+			}
+			got := &SearchAlertResponse{}
+			if err := json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), got); err != nil {
+				t.Fatalf("failed to unmarshal contents: %v", err)
+			}
+			gotIDs := []uint32{}
+			for _, a := range got.Alert {
+				gotIDs = append(gotIDs, a.AlertId)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Errorf("handler() alerts mismatch (-want +got):\n%s", diff)
+			}
+			if (got.NextCursor != "") != tt.wantNext {
+				t.Errorf("handler() next_cursor = %q, want next: %v", got.NextCursor, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package riskenmcp
+
+import "time"
+
+const (
+	defaultFindingFetchConcurrency = 5
+	maxFindingFetchConcurrency     = 50
+
+	defaultProjectCacheTTL  = 5 * time.Minute
+	defaultProjectCacheSize = 1000
+
+	defaultFetchAllMaxFindings = 500
+	defaultFetchAllMaxBytes    = 256 * 1024
+
+	defaultResourcePollInterval = time.Minute
+	minResourcePollInterval     = 10 * time.Second
+)
+
+// Config holds the tunable settings of the RISKEN MCP server.
+type Config struct {
+	// FindingFetchConcurrency is the maximum number of parallel GetFinding calls per request.
+	FindingFetchConcurrency int
+
+	// ProjectCacheTTL is how long the signin and project lookup result is cached per client.
+	ProjectCacheTTL time.Duration
+
+	// MaxArchiveExpiry is the maximum expiry of archived findings. Zero means no limit.
+	MaxArchiveExpiry time.Duration
+
+	// FetchAllMaxFindings is the maximum number of findings returned by search_finding in fetch_all mode.
+	FetchAllMaxFindings int
+
+	// FetchAllMaxBytes is the maximum JSON size of the findings returned by search_finding in fetch_all mode.
+	FetchAllMaxBytes int
+
+	// ResourcePollInterval is how often the subscribed finding:// and alert:// resources are polled for changes.
+	ResourcePollInterval time.Duration
+
+	// SigningKey is the key to sign the tokens issued to MCP clients (e.g. confirmation tokens).
+	// A random key is generated at startup if empty.
+	SigningKey string
+}
+
+// withDefaults returns a copy of the config with zero values replaced by defaults.
+func (c *Config) withDefaults() *Config {
+	cfg := Config{}
+	if c != nil {
+		cfg = *c
+	}
+	if cfg.FindingFetchConcurrency <= 0 {
+		cfg.FindingFetchConcurrency = defaultFindingFetchConcurrency
+	}
+	if cfg.FindingFetchConcurrency > maxFindingFetchConcurrency {
+		cfg.FindingFetchConcurrency = maxFindingFetchConcurrency
+	}
+	if cfg.ProjectCacheTTL <= 0 {
+		cfg.ProjectCacheTTL = defaultProjectCacheTTL
+	}
+	if cfg.FetchAllMaxFindings <= 0 {
+		cfg.FetchAllMaxFindings = defaultFetchAllMaxFindings
+	}
+	if cfg.FetchAllMaxBytes <= 0 {
+		cfg.FetchAllMaxBytes = defaultFetchAllMaxBytes
+	}
+	if cfg.ResourcePollInterval <= 0 {
+		cfg.ResourcePollInterval = defaultResourcePollInterval
+	}
+	if cfg.ResourcePollInterval < minResourcePollInterval {
+		cfg.ResourcePollInterval = minResourcePollInterval
+	}
+	return &cfg
+}
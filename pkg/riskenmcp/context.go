@@ -10,7 +10,8 @@ import (
 type contextKey string
 
 const (
-	RISKENClientContextKey contextKey = "risken_client"
+	RISKENClientContextKey    contextKey = "risken_client"
+	RISKENClientKeyContextKey contextKey = "risken_client_key"
 )
 
 // WithRISKENClient sets the RISKEN client in the context.
@@ -18,6 +19,12 @@ func WithRISKENClient(ctx context.Context, client *risken.Client) context.Contex
 	return context.WithValue(ctx, RISKENClientContextKey, client)
 }
 
+// WithRISKENClientKey sets the key identifying the RISKEN client (e.g. token hash) in the context.
+// The key is used to share cached data between requests of the same client.
+func WithRISKENClientKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, RISKENClientKeyContextKey, key)
+}
+
 // GetRISKENClient returns the RISKEN client from server field or context.
 func (s *Server) GetRISKENClient(ctx context.Context) (*risken.Client, error) {
 	if s.riskenClient != nil {
@@ -30,3 +37,11 @@ func (s *Server) GetRISKENClient(ctx context.Context) (*risken.Client, error) {
 	}
 	return client, nil
 }
+
+// getRISKENClientKey returns the client key from context, or the client address if the key is not set.
+func getRISKENClientKey(ctx context.Context, client *risken.Client) string {
+	if key, ok := ctx.Value(RISKENClientKeyContextKey).(string); ok && key != "" {
+		return key
+	}
+	return fmt.Sprintf("%p", client)
+}
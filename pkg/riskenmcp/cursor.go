@@ -0,0 +1,80 @@
+package riskenmcp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	cursorTTL           = 24 * time.Hour
+	cursorPurposePrefix = "cursor:"
+)
+
+// withCursor adds the cursor param to the list tools.
+func withCursor() mcp.ToolOption {
+	return mcp.WithString(
+		"cursor",
+		mcp.Description("Cursor of the next page (next_cursor in the previous response). If specified, the other parameters are ignored and the filters of the first page are used."),
+	)
+}
+
+// issueCursor signs the query of the next page. The cursor is bound to the tool and the project,
+// so a tampered cursor or a cursor issued to another project is rejected.
+func (s *Server) issueCursor(tool string, projectID uint32, query any) (string, error) {
+	return s.signer.sign(cursorPurposePrefix+tool, projectID, query, time.Now().Add(cursorTTL))
+}
+
+// parseCursor decodes the cursor param into query. It returns false if the cursor is not specified.
+func (s *Server) parseCursor(req mcp.CallToolRequest, tool string, projectID uint32, query any) (bool, error) {
+	cursor, err := helper.ParseMCPArgs[string]("cursor", req.GetArguments())
+	if err != nil {
+		return false, fmt.Errorf("cursor error: %s", err)
+	}
+	if cursor == nil || *cursor == "" {
+		return false, nil
+	}
+	if err := s.signer.verify(*cursor, cursorPurposePrefix+tool, projectID, query); err != nil {
+		return false, fmt.Errorf("cursor error: %s", err)
+	}
+	return true, nil
+}
+
+// keysetOffset returns the offset of the first item after lastID, the last item of the previous page.
+// RISKEN list APIs only page by offset, so the offset in the cursor shifts when items are added or removed before it.
+// idAt returns the ID at the offset in the ID order (false if out of range) and the total of the items.
+// The offset in the cursor is checked first, and searched again by binary search if the item before it moved.
+func keysetOffset(offset int32, lastID uint64, desc bool, idAt func(offset int32) (uint64, uint32, bool, error)) (int32, error) {
+	if offset <= 0 || lastID == 0 {
+		return offset, nil
+	}
+	id, total, ok, err := idAt(offset - 1)
+	if err != nil {
+		return 0, err
+	}
+	if ok && id == lastID {
+		return offset, nil
+	}
+	after := func(id uint64) bool {
+		if desc {
+			return id < lastID
+		}
+		return id > lastID
+	}
+	lo, hi := int64(0), int64(total)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		id, _, ok, err := idAt(int32(mid))
+		if err != nil {
+			return 0, err
+		}
+		if !ok || after(id) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return int32(lo), nil
+}
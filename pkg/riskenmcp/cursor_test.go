@@ -0,0 +1,223 @@
+package riskenmcp
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseCursor(t *testing.T) {
+	s := newTestServer(nil, nil)
+	query := &searchFindingQuery{
+		Request:   &finding.ListFindingRequest{ProjectId: 1, DataSource: []string{"aws"}, FromScore: 0.5, Offset: 10, Limit: 10},
+		TimeRange: &findingTimeRange{From: 1735689600},
+	}
+	cursor, err := s.issueCursor("search_finding", 1, query)
+	if err != nil {
+		t.Fatalf("issueCursor() error = %v", err)
+	}
+	otherProject, err := s.issueCursor("search_finding", 2, query)
+	if err != nil {
+		t.Fatalf("issueCursor() error = %v", err)
+	}
+	// Rewrite the payload without re-signing.
+	parts := strings.Split(cursor, ".")
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("failed to decode cursor: %v", err)
+	}
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(strings.Replace(string(claims), `"offset":10`, `"offset":0`, 1)))
+	tampered := strings.Join(parts, ".")
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		tool    string
+		want    *searchFindingQuery
+		wantOK  bool
+		wantErr bool
+	}{
+		{
+			name: "not specified",
+			args: map[string]any{},
+			tool: "search_finding",
+			want: &searchFindingQuery{},
+		},
+		{
+			name:   "valid",
+			args:   map[string]any{"cursor": cursor},
+			tool:   "search_finding",
+			want:   query,
+			wantOK: true,
+		},
+		{
+			name:    "tampered",
+			args:    map[string]any{"cursor": tampered},
+			tool:    "search_finding",
+			wantErr: true,
+		},
+		{
+			name:    "another project",
+			args:    map[string]any{"cursor": otherProject},
+			tool:    "search_finding",
+			wantErr: true,
+		},
+		{
+			name:    "another tool",
+			args:    map[string]any{"cursor": cursor},
+			tool:    "list_resources",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			got := &searchFindingQuery{}
+			ok, err := s.parseCursor(req, tt.tool, 1, got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCursor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if ok != tt.wantOK {
+				t.Errorf("parseCursor() = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(finding.ListFindingRequest{})); diff != "" {
+				t.Errorf("parseCursor() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPageAlerts(t *testing.T) {
+	s := newTestServer(nil, nil)
+	alerts := []*alert.Alert{{AlertId: 2}, {AlertId: 5}, {AlertId: 1}, {AlertId: 4}, {AlertId: 3}}
+	query := &searchAlertQuery{Request: &alert.ListAlertRequest{ProjectId: 1}, Limit: 2}
+
+	gotIDs := []uint32{}
+	for page := 0; ; page++ {
+		if page > len(alerts) {
+			t.Fatal("pageAlerts() did not reach the last page")
+		}
+		got, err := s.pageAlerts(query, alerts)
+		if err != nil {
+			t.Fatalf("pageAlerts() error = %v", err)
+		}
+		if got.Total != len(alerts) {
+			t.Errorf("pageAlerts() total = %d, want %d", got.Total, len(alerts))
+		}
+		for _, a := range got.Alert {
+			gotIDs = append(gotIDs, a.AlertId)
+		}
+		if got.NextCursor == "" {
+			break
+		}
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"cursor": got.NextCursor}
+		query = &searchAlertQuery{}
+		if _, err := s.parseCursor(req, "search_alert", 1, query); err != nil {
+			t.Fatalf("parseCursor() error = %v", err)
+		}
+	}
+	if diff := cmp.Diff([]uint32{5, 4, 3, 2, 1}, gotIDs); diff != "" {
+		t.Errorf("pageAlerts() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestKeysetOffset(t *testing.T) {
+	tests := []struct {
+		name      string
+		ids       []uint64
+		offset    int32
+		lastID    uint64
+		desc      bool
+		want      int32
+		wantCalls int
+	}{
+		{
+			name:      "unchanged",
+			ids:       []uint64{10, 9, 8, 7, 6, 5},
+			offset:    3,
+			lastID:    8,
+			desc:      true,
+			want:      3,
+			wantCalls: 1,
+		},
+		{
+			name:   "added before",
+			ids:    []uint64{12, 11, 10, 9, 8, 7, 6, 5},
+			offset: 3,
+			lastID: 8,
+			desc:   true,
+			want:   5,
+		},
+		{
+			name:   "removed before",
+			ids:    []uint64{10, 8, 7, 6, 5},
+			offset: 3,
+			lastID: 8,
+			desc:   true,
+			want:   2,
+		},
+		{
+			name:   "last item removed",
+			ids:    []uint64{10, 9, 7, 6, 5},
+			offset: 3,
+			lastID: 8,
+			desc:   true,
+			want:   2,
+		},
+		{
+			name:   "asc",
+			ids:    []uint64{1, 2, 3, 4, 5, 6},
+			offset: 2,
+			lastID: 3,
+			want:   3,
+		},
+		{
+			name:   "end of the items",
+			ids:    []uint64{10, 9},
+			offset: 3,
+			lastID: 8,
+			desc:   true,
+			want:   2,
+		},
+		{
+			name:   "first page",
+			ids:    []uint64{10, 9},
+			offset: 0,
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			got, err := keysetOffset(tt.offset, tt.lastID, tt.desc, func(offset int32) (uint64, uint32, bool, error) {
+				calls++
+				if int(offset) >= len(tt.ids) {
+					return 0, uint32(len(tt.ids)), false, nil
+				}
+				return tt.ids[offset], uint32(len(tt.ids)), true, nil
+			})
+			if err != nil {
+				t.Fatalf("keysetOffset() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("keysetOffset() = %d, want %d", got, tt.want)
+			}
+			if tt.wantCalls > 0 && calls != tt.wantCalls {
+				t.Errorf("keysetOffset() calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
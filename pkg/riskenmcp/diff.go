@@ -0,0 +1,83 @@
+package riskenmcp
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldChange is a changed field between the before and after states.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// diffFields compares the exported fields of the structs by json name.
+// before or after can be nil, e.g. for created or deleted objects.
+func diffFields(before, after any) []*FieldChange {
+	beforeFields := structFields(before)
+	afterFields := structFields(after)
+	names := []string{}
+	seen := map[string]bool{}
+	for _, fields := range [][]namedField{beforeFields, afterFields} {
+		for _, f := range fields {
+			if !seen[f.name] {
+				seen[f.name] = true
+				names = append(names, f.name)
+			}
+		}
+	}
+
+	changes := []*FieldChange{}
+	for _, name := range names {
+		b := fieldValue(beforeFields, name)
+		a := fieldValue(afterFields, name)
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+		changes = append(changes, &FieldChange{Field: name, Before: b, After: a})
+	}
+	return changes
+}
+
+type namedField struct {
+	name  string
+	value any
+}
+
+// structFields returns the exported fields of the struct (or the pointer to struct) in declaration order.
+func structFields(v any) []namedField {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return nil
+	}
+	rv = reflect.Indirect(rv)
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := []namedField{}
+	for i := range rv.NumField() {
+		field := rv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, namedField{name: name, value: rv.Field(i).Interface()})
+	}
+	return fields
+}
+
+func fieldValue(fields []namedField, name string) any {
+	for _, f := range fields {
+		if f.name == name {
+			return f.value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package riskenmcp
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffFields(t *testing.T) {
+	type object struct {
+		ID      uint32   `json:"id"`
+		Name    string   `json:"name,omitempty"`
+		Enabled bool     `json:"enabled"`
+		IDs     []uint32 `json:"ids"`
+		ignored string
+	}
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		want   []*FieldChange
+	}{
+		{
+			name:   "updated",
+			before: &object{ID: 1, Name: "a", Enabled: true, IDs: []uint32{1}, ignored: "x"},
+			after:  &object{ID: 1, Name: "b", Enabled: false, IDs: []uint32{1}, ignored: "y"},
+			want: []*FieldChange{
+				{Field: "name", Before: "a", After: "b"},
+				{Field: "enabled", Before: true, After: false},
+			},
+		},
+		{
+			name:   "created",
+			before: (*object)(nil),
+			after:  &object{ID: 1, Name: "a"},
+			want: []*FieldChange{
+				{Field: "id", Before: nil, After: uint32(1)},
+				{Field: "name", Before: nil, After: "a"},
+				{Field: "enabled", Before: nil, After: false},
+				{Field: "ids", Before: nil, After: []uint32(nil)},
+			},
+		},
+		{
+			name:   "no change",
+			before: &object{ID: 1},
+			after:  &object{ID: 1},
+			want:   []*FieldChange{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffFields(tt.before, tt.after)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("diffFields() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 
 	"github.com/ca-risken/core/proto/finding"
-	"github.com/ca-risken/risken-mcp-server/pkg/helper"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -27,24 +26,22 @@ func (s *Server) FindingResourceContentsHandler() func(ctx context.Context, requ
 			return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
 		}
 
-		p, err := s.GetCurrentProject(ctx, riskenClient)
+		p, err := s.getResourceProject(ctx, riskenClient, request)
 		if err != nil {
-			return nil, errors.New("failed to get project")
+			return nil, err
 		}
-		findingID, err := helper.ParseMCPArgs[uint64]("finding_id", request.Params.Arguments)
+		findingID, err := parseResourceID("finding_id", request)
 		if err != nil {
-			return nil, errors.New("failed to parse finding_id")
-		}
-		if findingID == nil {
-			return nil, errors.New("finding_id is required")
+			return nil, err
 		}
 
 		// Call RISKEN API
 		finding, err := riskenClient.GetFinding(ctx, &finding.GetFindingRequest{
 			ProjectId: p.ProjectId,
-			FindingId: *findingID,
+			FindingId: findingID,
 		})
 		if err != nil {
+			s.invalidateOnAuthError(ctx, riskenClient, err)
 			return nil, errors.New("failed to get finding")
 		}
 		jsonData, err := json.Marshal(finding)
@@ -58,6 +55,11 @@ func (s *Server) FindingResourceContentsHandler() func(ctx context.Context, requ
 				MIMEType: "application/json",
 				Text:     string(jsonData),
 			},
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "text/markdown",
+				Text:     renderFindingMarkdown(finding.Finding),
+			},
 		}, nil
 	}
 }
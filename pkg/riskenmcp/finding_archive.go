@@ -3,7 +3,9 @@ package riskenmcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ca-risken/core/proto/finding"
@@ -13,6 +15,24 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+const (
+	archiveNotePrefix = "Archived by MCP"
+
+	// defaultArchiveExpiry is used when neither the expiry params nor Config.MaxArchiveExpiry is specified.
+	defaultArchiveExpiry = time.Hour * 24 * 365 * 100
+
+	// maxArchiveExpiresInDays caps expires_in_days so that the expiry does not overflow time.Duration.
+	maxArchiveExpiresInDays = 36500
+)
+
+// Archive reasons. Only false_positive has a dedicated PendReason in RISKEN,
+// so the other reasons are recorded in the note prefix. e.g. "Archived by MCP(risk_accepted): ..."
+const (
+	archiveReasonFalsePositive = "false_positive"
+	archiveReasonRiskAccepted  = "risk_accepted"
+	archiveReasonPendingFix    = "pending_fix"
+)
+
 func (s *Server) ArchiveFinding() (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("archive_finding",
 			mcp.WithDescription("Archive RISKEN finding. Use this when a request include \"archive\", \"アーカイブ\", \"ペンディング\"..."),
@@ -26,6 +46,21 @@ func (s *Server) ArchiveFinding() (tool mcp.Tool, handler server.ToolHandlerFunc
 				mcp.Description("Note. ex) This is no risk finding."),
 				mcp.DefaultString("Archived by MCP"),
 			),
+			mcp.WithString(
+				"reason",
+				mcp.Description("Reason of the archive. false_positive: not a real risk, risk_accepted: the risk is accepted for a while, pending_fix: the fix is in progress"),
+				mcp.Enum(archiveReasonFalsePositive, archiveReasonRiskAccepted, archiveReasonPendingFix),
+			),
+			mcp.WithNumber(
+				"expires_in_days",
+				mcp.Description("The archive expires after the days, and the finding becomes active again. Cannot be used with expires_at."),
+				mcp.Min(1),
+				mcp.Max(maxArchiveExpiresInDays),
+			),
+			mcp.WithString(
+				"expires_at",
+				mcp.Description("The archive expires at the time (ISO8601. e.g. 2025-12-31, 2025-12-31T00:00:00Z), and the finding becomes active again. Cannot be used with expires_in_days."),
+			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			riskenClient, err := s.GetRISKENClient(ctx)
@@ -42,6 +77,7 @@ func (s *Server) ArchiveFinding() (tool mcp.Tool, handler server.ToolHandlerFunc
 			// Call RISKEN API
 			resp, err := riskenClient.PutPendFinding(ctx, params)
 			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
 				return mcp.NewToolResultError(fmt.Sprintf("failed to archive finding: %s", err)), nil
 			}
 			jsonData, err := json.Marshal(resp)
@@ -57,34 +93,269 @@ func (s *Server) ParseArchiveFindingParams(ctx context.Context, req mcp.CallTool
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project: %s", err)
 	}
-	param := &finding.PutPendFindingRequest{
-		ProjectId: p.ProjectId,
-		PendFinding: &finding.PendFindingForUpsert{
-			ProjectId: p.ProjectId,
-			ExpiredAt: time.Now().Add(time.Hour * 24 * 365 * 100).Unix(),
-		},
-	}
 
 	findingID, err := helper.ParseMCPArgs[float64]("finding_id", req.GetArguments())
 	if err != nil {
 		return nil, fmt.Errorf("finding_id error: %s", err)
 	}
-	if findingID != nil {
-		param.PendFinding.FindingId = uint64(*findingID)
+	if findingID == nil {
+		return nil, errors.New("finding_id is required")
+	}
+	pend, err := s.parsePendFindingArgs(req, time.Now())
+	if err != nil {
+		return nil, err
 	}
+	pend.ProjectId = p.ProjectId
+	pend.FindingId = uint64(*findingID)
+
+	return &finding.PutPendFindingRequest{
+		ProjectId:   p.ProjectId,
+		PendFinding: pend,
+	}, nil
+}
+
+// parsePendFindingArgs parses the note, reason and expiry params shared by the archive tools.
+func (s *Server) parsePendFindingArgs(req mcp.CallToolRequest, now time.Time) (*finding.PendFindingForUpsert, error) {
+	pend := &finding.PendFindingForUpsert{}
+
+	reason, err := helper.ParseMCPArgs[string]("reason", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("reason error: %s", err)
+	}
+	prefix := archiveNotePrefix
+	if reason != nil && *reason != "" {
+		switch *reason {
+		case archiveReasonFalsePositive:
+			pend.Reason = finding.PendReason_PEND_REASON_FALSE_POSITIVE
+		case archiveReasonRiskAccepted, archiveReasonPendingFix:
+			pend.Reason = finding.PendReason_PEND_REASON_UNKNOWN
+		default:
+			return nil, fmt.Errorf("invalid reason: %s", *reason)
+		}
+		prefix = fmt.Sprintf("%s(%s)", archiveNotePrefix, *reason)
+	}
+
 	note, err := helper.ParseMCPArgs[string]("note", req.GetArguments())
 	if err != nil {
 		return nil, fmt.Errorf("note error: %s", err)
 	}
-	if note != nil {
-		param.PendFinding.Note = *note
+	if note == nil || *note == "" || *note == archiveNotePrefix {
+		pend.Note = prefix
+	} else {
+		pend.Note = fmt.Sprintf("%s: %s", prefix, *note)
 	}
 
-	if param.PendFinding.Note == "" {
-		param.PendFinding.Note = "Archived by MCP"
-	} else {
-		param.PendFinding.Note = fmt.Sprintf("Archived by MCP: %s", *note)
+	expiredAt, err := s.parseArchiveExpiry(req, now)
+	if err != nil {
+		return nil, err
+	}
+	pend.ExpiredAt = expiredAt.Unix()
+	return pend, nil
+}
+
+// parseArchiveExpiry returns the expiry of the archive from expires_in_days or expires_at.
+// The expiry cannot exceed Config.MaxArchiveExpiry, which is also the default when it is configured.
+func (s *Server) parseArchiveExpiry(req mcp.CallToolRequest, now time.Time) (time.Time, error) {
+	maxExpiry := s.config.MaxArchiveExpiry
+	expiredAt := now.Add(defaultArchiveExpiry)
+	if maxExpiry > 0 {
+		expiredAt = now.Add(maxExpiry)
+	}
+
+	expiresInDays, err := helper.ParseMCPArgs[float64]("expires_in_days", req.GetArguments())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expires_in_days error: %s", err)
+	}
+	expiresAt, err := helper.ParseMCPArgs[string]("expires_at", req.GetArguments())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expires_at error: %s", err)
+	}
+	if expiresAt != nil && *expiresAt == "" {
+		expiresAt = nil
+	}
+
+	switch {
+	case expiresInDays != nil && expiresAt != nil:
+		return time.Time{}, errors.New("expires_in_days and expires_at cannot be used together")
+	case expiresInDays != nil:
+		if *expiresInDays < 1 {
+			return time.Time{}, fmt.Errorf("expires_in_days must be 1 or more: %v", *expiresInDays)
+		}
+		if *expiresInDays > maxArchiveExpiresInDays {
+			return time.Time{}, fmt.Errorf("expires_in_days must be %d or less: %v", maxArchiveExpiresInDays, *expiresInDays)
+		}
+		expiredAt = now.Add(time.Duration(*expiresInDays * float64(24*time.Hour)))
+	case expiresAt != nil:
+		t, err := helper.ParseISO8601Time(*expiresAt)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("expires_at error: %s", err)
+		}
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("expires_at must be in the future: %s", *expiresAt)
+		}
+		expiredAt = t
+	default:
+		return expiredAt, nil
+	}
+
+	if maxExpiry > 0 && expiredAt.After(now.Add(maxExpiry)) {
+		return time.Time{}, fmt.Errorf("the expiry exceeds the server maximum(%d days): %s", int(maxExpiry.Hours()/24), expiredAt.UTC().Format(time.RFC3339))
+	}
+	return expiredAt, nil
+}
+
+func (s *Server) UnarchiveFinding() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("unarchive_finding",
+			mcp.WithDescription("Unarchive RISKEN finding (make the archived finding active again). Use this when a request include \"unarchive\", \"restore\", \"アーカイブ解除\", \"ペンディング解除\"..."),
+			mcp.WithNumber(
+				"finding_id",
+				mcp.Description("Finding ID."),
+				mcp.Required(),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			params, err := s.ParseUnarchiveFindingParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			if err := riskenClient.DeletePendFinding(ctx, params); err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to unarchive finding: %s", err)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Successfully unarchived the finding(finding_id=%d)", params.FindingId)), nil
+		}
+}
+
+func (s *Server) ParseUnarchiveFindingParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*finding.DeletePendFindingRequest, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	findingID, err := helper.ParseMCPArgs[float64]("finding_id", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("finding_id error: %s", err)
+	}
+	if findingID == nil {
+		return nil, errors.New("finding_id is required")
+	}
+	return &finding.DeletePendFindingRequest{
+		ProjectId: p.ProjectId,
+		FindingId: uint64(*findingID),
+	}, nil
+}
+
+// ArchiveInfo is the archive (pend) record of a finding.
+type ArchiveInfo struct {
+	FindingID  uint64 `json:"finding_id"`
+	Archived   bool   `json:"archived"`
+	Expired    bool   `json:"expired,omitempty"`
+	Note       string `json:"note,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	PendUserID uint32 `json:"pend_user_id,omitempty"`
+	CreatedAt  string `json:"created_at,omitempty"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+	ExpiredAt  string `json:"expired_at,omitempty"`
+}
+
+func (s *Server) GetArchiveInfo() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_archive_info",
+			mcp.WithDescription("Get the archive information (note, reason, expiry) of RISKEN finding. Use this when a request include \"why archived\", \"archive reason\", \"アーカイブ理由\"..."),
+			mcp.WithNumber(
+				"finding_id",
+				mcp.Description("Finding ID."),
+				mcp.Required(),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			params, err := s.ParseGetArchiveInfoParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			resp, err := riskenClient.GetPendFinding(ctx, params)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get archive info: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(newArchiveInfo(params.FindingId, resp.PendFinding, time.Now()))
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) ParseGetArchiveInfoParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*finding.GetPendFindingRequest, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %s", err)
 	}
+	findingID, err := helper.ParseMCPArgs[float64]("finding_id", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("finding_id error: %s", err)
+	}
+	if findingID == nil {
+		return nil, errors.New("finding_id is required")
+	}
+	return &finding.GetPendFindingRequest{
+		ProjectId: p.ProjectId,
+		FindingId: uint64(*findingID),
+	}, nil
+}
+
+func newArchiveInfo(findingID uint64, pend *finding.PendFinding, now time.Time) *ArchiveInfo {
+	if pend == nil || pend.FindingId == 0 {
+		return &ArchiveInfo{FindingID: findingID}
+	}
+	info := &ArchiveInfo{
+		FindingID:  findingID,
+		Archived:   true,
+		Note:       pend.Note,
+		Reason:     archiveReason(pend),
+		PendUserID: pend.PendUserId,
+		CreatedAt:  formatUnixTime(pend.CreatedAt),
+		UpdatedAt:  formatUnixTime(pend.UpdatedAt),
+		ExpiredAt:  formatUnixTime(pend.ExpiredAt),
+	}
+	if pend.ExpiredAt > 0 && pend.ExpiredAt <= now.Unix() {
+		info.Archived = false
+		info.Expired = true
+	}
+	return info
+}
+
+// archiveReason returns the archive reason from the PendReason, or from the note prefix set by archive tools.
+func archiveReason(pend *finding.PendFinding) string {
+	if pend.Reason == finding.PendReason_PEND_REASON_FALSE_POSITIVE {
+		return archiveReasonFalsePositive
+	}
+	for _, reason := range []string{archiveReasonRiskAccepted, archiveReasonPendingFix} {
+		if strings.HasPrefix(pend.Note, fmt.Sprintf("%s(%s)", archiveNotePrefix, reason)) {
+			return reason
+		}
+	}
+	return ""
+}
 
-	return param, nil
+// formatUnixTime formats the unix time in RFC3339 (UTC), or returns empty for zero.
+func formatUnixTime(unix int64) string {
+	if unix <= 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
 }
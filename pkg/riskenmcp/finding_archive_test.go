@@ -0,0 +1,185 @@
+package riskenmcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewArchiveInfo(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		pend *finding.PendFinding
+		want *ArchiveInfo
+	}{
+		{
+			name: "not archived",
+			pend: nil,
+			want: &ArchiveInfo{FindingID: 1},
+		},
+		{
+			name: "archived",
+			pend: &finding.PendFinding{
+				FindingId: 1,
+				Note:      "Archived by MCP: no risk",
+				Reason:    finding.PendReason_PEND_REASON_FALSE_POSITIVE,
+				CreatedAt: now.Add(-time.Hour).Unix(),
+				UpdatedAt: now.Add(-time.Hour).Unix(),
+				ExpiredAt: now.Add(24 * time.Hour).Unix(),
+			},
+			want: &ArchiveInfo{
+				FindingID: 1,
+				Archived:  true,
+				Note:      "Archived by MCP: no risk",
+				Reason:    "false_positive",
+				CreatedAt: "2024-12-31T23:00:00Z",
+				UpdatedAt: "2024-12-31T23:00:00Z",
+				ExpiredAt: "2025-01-02T00:00:00Z",
+			},
+		},
+		{
+			name: "reason in note",
+			pend: &finding.PendFinding{
+				FindingId: 1,
+				Note:      "Archived by MCP(risk_accepted): accepted until Q3",
+				ExpiredAt: now.Add(24 * time.Hour).Unix(),
+			},
+			want: &ArchiveInfo{
+				FindingID: 1,
+				Archived:  true,
+				Note:      "Archived by MCP(risk_accepted): accepted until Q3",
+				Reason:    "risk_accepted",
+				ExpiredAt: "2025-01-02T00:00:00Z",
+			},
+		},
+		{
+			name: "expired",
+			pend: &finding.PendFinding{
+				FindingId: 1,
+				Note:      "Archived by MCP",
+				ExpiredAt: now.Add(-time.Minute).Unix(),
+			},
+			want: &ArchiveInfo{
+				FindingID: 1,
+				Expired:   true,
+				Note:      "Archived by MCP",
+				ExpiredAt: "2024-12-31T23:59:00Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newArchiveInfo(1, tt.pend, now)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("newArchiveInfo() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePendFindingArgs(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		maxExpiry time.Duration
+		args      map[string]any
+		want      *finding.PendFindingForUpsert
+		wantErr   bool
+	}{
+		{
+			name: "default",
+			args: map[string]any{"note": "Archived by MCP"},
+			want: &finding.PendFindingForUpsert{
+				Note:      "Archived by MCP",
+				ExpiredAt: now.Add(defaultArchiveExpiry).Unix(),
+			},
+		},
+		{
+			name:      "default with max expiry",
+			maxExpiry: 90 * 24 * time.Hour,
+			args:      map[string]any{},
+			want: &finding.PendFindingForUpsert{
+				Note:      "Archived by MCP",
+				ExpiredAt: now.Add(90 * 24 * time.Hour).Unix(),
+			},
+		},
+		{
+			name: "false positive",
+			args: map[string]any{"note": "test data", "reason": "false_positive", "expires_in_days": 30.0},
+			want: &finding.PendFindingForUpsert{
+				Note:      "Archived by MCP(false_positive): test data",
+				Reason:    finding.PendReason_PEND_REASON_FALSE_POSITIVE,
+				ExpiredAt: now.Add(30 * 24 * time.Hour).Unix(),
+			},
+		},
+		{
+			name: "risk accepted",
+			args: map[string]any{"reason": "risk_accepted", "expires_at": "2025-04-01"},
+			want: &finding.PendFindingForUpsert{
+				Note:      "Archived by MCP(risk_accepted)",
+				Reason:    finding.PendReason_PEND_REASON_UNKNOWN,
+				ExpiredAt: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC).Unix(),
+			},
+		},
+		{
+			name:    "invalid reason",
+			args:    map[string]any{"reason": "unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "both expiry params",
+			args:    map[string]any{"expires_in_days": 30.0, "expires_at": "2025-04-01"},
+			wantErr: true,
+		},
+		{
+			name:    "past expires_at",
+			args:    map[string]any{"expires_at": "2024-12-31"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid expires_at",
+			args:    map[string]any{"expires_at": "next month"},
+			wantErr: true,
+		},
+		{
+			name: "max expires_in_days",
+			args: map[string]any{"expires_in_days": float64(maxArchiveExpiresInDays)},
+			want: &finding.PendFindingForUpsert{
+				Note:      "Archived by MCP",
+				ExpiredAt: now.Add(maxArchiveExpiresInDays * 24 * time.Hour).Unix(),
+			},
+		},
+		{
+			name:    "too large expires_in_days",
+			args:    map[string]any{"expires_in_days": 1e9},
+			wantErr: true,
+		},
+		{
+			name:      "exceeds max expiry",
+			maxExpiry: 90 * 24 * time.Hour,
+			args:      map[string]any{"expires_in_days": 91.0},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(nil, &Config{MaxArchiveExpiry: tt.maxExpiry})
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			got, err := s.parsePendFindingArgs(req, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePendFindingArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(finding.PendFindingForUpsert{})); diff != "" {
+				t.Errorf("parsePendFindingArgs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
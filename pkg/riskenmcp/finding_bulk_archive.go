@@ -0,0 +1,284 @@
+package riskenmcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	bulkArchivePageSize    = 100
+	bulkArchiveMaxFindings = 1000
+	bulkArchiveTokenTTL    = 10 * time.Minute
+	bulkArchiveMaxPlans    = 1000
+)
+
+// bulkArchivePlan is the previewed archive operation. It is kept in the server and
+// the confirmation token is only its random key, so the client does not need to echo back the finding IDs.
+type bulkArchivePlan struct {
+	ProjectID  uint32
+	FindingIDs []uint64
+	Note       string
+	Reason     finding.PendReason
+	ExpiredAt  int64
+}
+
+type BulkArchiveResponse struct {
+	DryRun              bool                 `json:"dry_run"`
+	Total               int                  `json:"total"`
+	FindingIDs          []uint64             `json:"finding_ids,omitempty"`
+	Note                string               `json:"note,omitempty"`
+	ExpiredAt           string               `json:"expired_at,omitempty"`
+	ConfirmationToken   string               `json:"confirmation_token,omitempty"`
+	ConfirmationExpires string               `json:"confirmation_expires_at,omitempty"`
+	Succeeded           int                  `json:"succeeded"`
+	Failed              int                  `json:"failed"`
+	Results             []*BulkArchiveResult `json:"results,omitempty"`
+}
+
+// BulkArchiveResult is the archive result of each finding.
+type BulkArchiveResult struct {
+	FindingID uint64 `json:"finding_id"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (s *Server) BulkArchiveFindings() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("bulk_archive_findings",
+			mcp.WithDescription(fmt.Sprintf("Archive the active RISKEN findings matched by the filters at once (max %d findings). "+
+				"By default, this is a dry run that returns the target finding IDs and a confirmation_token. "+
+				"To execute, call again with dry_run=false and the confirmation_token; then exactly the previewed findings are archived with the previewed options. "+
+				"The confirmation_token can be used only once. "+
+				"Use this when a request include \"bulk archive\", \"一括アーカイブ\"...", bulkArchiveMaxFindings)),
+			mcp.WithArray(
+				"data_source",
+				mcp.Description("RISKEN DataSource. e.g. aws, google, code (like github, gitlab, etc.), osint, diagnosis, azure, ..."),
+				mcp.Enum(findingDataSources...),
+			),
+			mcp.WithArray(
+				"resource_name",
+				mcp.Description("RISKEN ResourceName. e.g. \"arn:aws:iam::123456789012:user/test-user\" ..."),
+			),
+			mcp.WithArray(
+				"tag",
+				mcp.Description("Tag of the findings. e.g. \"ticket:SEC-123\", \"owner:payments\" ..."),
+			),
+			mcp.WithNumber(
+				"from_score",
+				mcp.Description("Minimum score of the findings."),
+				mcp.Max(1.0),
+				mcp.Min(0.0),
+			),
+			mcp.WithNumber(
+				"to_score",
+				mcp.Description("Maximum score of the findings."),
+				mcp.Max(1.0),
+				mcp.Min(0.0),
+			),
+			mcp.WithString(
+				"note",
+				mcp.Description("Note. ex) Stale OSINT findings."),
+			),
+			mcp.WithString(
+				"reason",
+				mcp.Description("Reason of the archive. false_positive: not a real risk, risk_accepted: the risk is accepted for a while, pending_fix: the fix is in progress"),
+				mcp.Enum(archiveReasonFalsePositive, archiveReasonRiskAccepted, archiveReasonPendingFix),
+			),
+			mcp.WithNumber(
+				"expires_in_days",
+				mcp.Description("The archive expires after the days. Cannot be used with expires_at."),
+				mcp.Min(1),
+			),
+			mcp.WithString(
+				"expires_at",
+				mcp.Description("The archive expires at the time (ISO8601. e.g. 2025-12-31, 2025-12-31T00:00:00Z). Cannot be used with expires_in_days."),
+			),
+			mcp.WithBoolean(
+				"dry_run",
+				mcp.Description("If true, only preview the target findings without archiving."),
+				mcp.DefaultBool(true),
+			),
+			mcp.WithString(
+				"confirmation_token",
+				mcp.Description("Confirmation token returned by the dry run. Required when dry_run is false. It can be used only once."),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+			p, err := s.GetCurrentProject(ctx, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %s", err)), nil
+			}
+
+			dryRun, err := helper.ParseMCPArgs[bool]("dry_run", req.GetArguments())
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: dry_run error: %s", err)), nil
+			}
+			var resp *BulkArchiveResponse
+			if dryRun == nil || *dryRun {
+				resp, err = s.previewBulkArchive(ctx, req, riskenClient, p.ProjectId)
+			} else {
+				resp, err = s.executeBulkArchive(ctx, req, riskenClient, p.ProjectId)
+			}
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to bulk archive findings: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(resp)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+// previewBulkArchive lists the target findings and issues the confirmation token of the plan.
+func (s *Server) previewBulkArchive(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client, projectID uint32) (*BulkArchiveResponse, error) {
+	param := &finding.ListFindingRequest{
+		ProjectId: projectID,
+		Status:    finding.FindingStatus_FINDING_ACTIVE,
+	}
+	if err := parseFindingFilterArgs(req, param); err != nil {
+		return nil, err
+	}
+	pend, err := s.parsePendFindingArgs(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	findingIDs, err := listAllFindingIDs(ctx, riskenClient, param, bulkArchiveMaxFindings)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &BulkArchiveResponse{
+		DryRun:     true,
+		Total:      len(findingIDs),
+		FindingIDs: findingIDs,
+		Note:       pend.Note,
+		ExpiredAt:  formatUnixTime(pend.ExpiredAt),
+	}
+	if len(findingIDs) == 0 {
+		return resp, nil
+	}
+	token, err := newConfirmationToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(bulkArchiveTokenTTL)
+	s.bulkArchivePlans.Set(token, &bulkArchivePlan{
+		ProjectID:  projectID,
+		FindingIDs: findingIDs,
+		Note:       pend.Note,
+		Reason:     pend.Reason,
+		ExpiredAt:  pend.ExpiredAt,
+	})
+	resp.ConfirmationToken = token
+	resp.ConfirmationExpires = expiresAt.UTC().Format(time.RFC3339)
+	return resp, nil
+}
+
+// executeBulkArchive archives the findings in the confirmed plan.
+func (s *Server) executeBulkArchive(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client, projectID uint32) (*BulkArchiveResponse, error) {
+	token, err := helper.ParseMCPArgs[string]("confirmation_token", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("confirmation_token error: %s", err)
+	}
+	if token == nil || *token == "" {
+		return nil, errors.New("confirmation_token is required to execute. Run with dry_run=true first")
+	}
+	// The plan is taken out of the store, so the token cannot be used twice.
+	plan, ok := s.bulkArchivePlans.Take(*token)
+	if !ok || plan.ProjectID != projectID {
+		return nil, errors.New("invalid or expired confirmation_token. Run with dry_run=true again")
+	}
+
+	results := make([]*BulkArchiveResult, len(plan.FindingIDs))
+	s.runParallel(len(plan.FindingIDs), func(i int) {
+		results[i] = archiveFinding(ctx, riskenClient, projectID, plan, plan.FindingIDs[i])
+	})
+
+	resp := &BulkArchiveResponse{
+		Total:     len(plan.FindingIDs),
+		Note:      plan.Note,
+		ExpiredAt: formatUnixTime(plan.ExpiredAt),
+		Results:   results,
+	}
+	for _, r := range results {
+		if r.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	s.logger.InfoContext(ctx, "Bulk archived findings",
+		slog.Any("project_id", projectID),
+		slog.Int("succeeded", resp.Succeeded),
+		slog.Int("failed", resp.Failed))
+	return resp, nil
+}
+
+func archiveFinding(ctx context.Context, riskenClient *risken.Client, projectID uint32, plan *bulkArchivePlan, findingID uint64) *BulkArchiveResult {
+	result := &BulkArchiveResult{FindingID: findingID}
+	if err := ctx.Err(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	_, err := riskenClient.PutPendFinding(ctx, &finding.PutPendFindingRequest{
+		ProjectId: projectID,
+		PendFinding: &finding.PendFindingForUpsert{
+			ProjectId: projectID,
+			FindingId: findingID,
+			Note:      plan.Note,
+			Reason:    plan.Reason,
+			ExpiredAt: plan.ExpiredAt,
+		},
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Success = true
+	return result
+}
+
+// newConfirmationToken returns a random token to look up the bulk archive plan.
+func newConfirmationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// listAllFindingIDs walks all pages of ListFinding. It fails if more than maxFindings findings are matched.
+func listAllFindingIDs(ctx context.Context, riskenClient *risken.Client, param *finding.ListFindingRequest, maxFindings int) ([]uint64, error) {
+	findingIDs := []uint64{}
+	param.Limit = bulkArchivePageSize
+	for param.Offset = 0; ; param.Offset += bulkArchivePageSize {
+		resp, err := riskenClient.ListFinding(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		if int(resp.Total) > maxFindings {
+			return nil, fmt.Errorf("too many findings matched (%d > %d), narrow down the filters", resp.Total, maxFindings)
+		}
+		findingIDs = append(findingIDs, resp.FindingId...)
+		if len(resp.FindingId) < bulkArchivePageSize || len(findingIDs) >= int(resp.Total) {
+			return findingIDs, nil
+		}
+	}
+}
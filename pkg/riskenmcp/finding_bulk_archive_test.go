@@ -0,0 +1,137 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/google/go-cmp/cmp"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBulkArchiveFindings(t *testing.T) {
+	var signinCount int32
+	projectHandler := newTestProjectHandler(&signinCount)
+	var mu sync.Mutex
+	archived := []uint64{}
+	client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/finding/list-finding":
+			// 150 findings in 2 pages
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			ids := []uint64{}
+			for id := offset + 1; id <= min(offset+bulkArchivePageSize, 150); id++ {
+				ids = append(ids, uint64(id))
+			}
+			data, _ := json.Marshal(ids)
+			_, _ = fmt.Fprintf(w, `{"data":{"finding_id":%s,"count":%d,"total":150}}`, data, len(ids))
+		case "/api/v1/finding/put-pend-finding":
+			req := &finding.PutPendFindingRequest{}
+			_ = json.NewDecoder(r.Body).Decode(req)
+			if req.PendFinding.FindingId == 3 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			mu.Lock()
+			archived = append(archived, req.PendFinding.FindingId)
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"data":{"pend_finding":{}}}`))
+		default:
+			projectHandler(w, r)
+		}
+	})
+	s := newTestServer(client, nil)
+	_, handler := s.BulkArchiveFindings()
+	ctx := context.Background()
+
+	call := func(args map[string]any) *BulkArchiveResponse {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handler(ctx, req)
+		if err != nil {
+			t.Fatalf("handler() error = %v", err)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		if result.IsError {
+			t.Fatalf("handler() result error = %s", text)
+		}
+		resp := &BulkArchiveResponse{}
+		if err := json.Unmarshal([]byte(text), resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return resp
+	}
+
+	// Dry run
+	preview := call(map[string]any{"data_source": []any{"osint"}, "reason": "risk_accepted", "expires_in_days": 30.0})
+	if !preview.DryRun || preview.Total != 150 || len(preview.FindingIDs) != 150 || preview.ConfirmationToken == "" {
+		t.Fatalf("preview = dry_run:%v total:%d ids:%d token:%q, want dry run of 150 findings with token",
+			preview.DryRun, preview.Total, len(preview.FindingIDs), preview.ConfirmationToken)
+	}
+	if len(preview.ConfirmationToken) != 32 {
+		t.Errorf("len(preview.ConfirmationToken) = %d, want 32", len(preview.ConfirmationToken))
+	}
+	if len(archived) != 0 {
+		t.Fatalf("archived %d findings in dry run", len(archived))
+	}
+
+	// Execution without token
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"dry_run": false}
+	result, err := handler(ctx, req)
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if !result.IsError {
+		t.Error("handler() without confirmation_token should return error")
+	}
+
+	// Execution
+	executed := call(map[string]any{"dry_run": false, "confirmation_token": preview.ConfirmationToken})
+	if executed.Succeeded != 149 || executed.Failed != 1 || len(executed.Results) != 150 {
+		t.Errorf("executed = succeeded:%d failed:%d results:%d, want 149, 1, 150", executed.Succeeded, executed.Failed, len(executed.Results))
+	}
+	if diff := cmp.Diff(&BulkArchiveResult{FindingID: 3, Error: executed.Results[2].Error}, executed.Results[2]); diff != "" || executed.Results[2].Error == "" {
+		t.Errorf("executed.Results[2] mismatch (-want +got):\n%s", diff)
+	}
+	if executed.Note != "Archived by MCP(risk_accepted)" {
+		t.Errorf("executed.Note = %q", executed.Note)
+	}
+
+	// Replay of the used token
+	req.Params.Arguments = map[string]any{"dry_run": false, "confirmation_token": preview.ConfirmationToken}
+	result, err = handler(ctx, req)
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if !result.IsError {
+		t.Error("handler() with the used confirmation_token should return error")
+	}
+	if len(archived) != 149 {
+		t.Errorf("archived %d findings, want 149", len(archived))
+	}
+}
+
+func TestExecuteBulkArchiveAnotherProject(t *testing.T) {
+	var signinCount int32
+	s := newTestServer(newTestRISKENClient(t, newTestProjectHandler(&signinCount)), nil)
+	s.bulkArchivePlans.Set("token", &bulkArchivePlan{ProjectID: 2, FindingIDs: []uint64{1}})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"dry_run": false, "confirmation_token": "token"}
+	_, handler := s.BulkArchiveFindings()
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if !result.IsError {
+		t.Error("handler() with the token of another project should return error")
+	}
+}
@@ -0,0 +1,302 @@
+package riskenmcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ca-risken/core/proto/finding"
+)
+
+// Formats of finding.Data in search_finding
+const (
+	findingDataFormatRaw      = "raw"
+	findingDataFormatMarkdown = "markdown"
+)
+
+// maxFindingDataValueLength limits the number of characters of a value in the Markdown summary.
+const maxFindingDataValueLength = 200
+
+// findingDataSummary is the readable summary of finding.Data: what was found, where and why.
+type findingDataSummary struct {
+	What  []*findingDataField
+	Where []*findingDataField
+	Why   []*findingDataField
+}
+
+func (s *findingDataSummary) empty() bool {
+	return len(s.What) == 0 && len(s.Where) == 0 && len(s.Why) == 0
+}
+
+type findingDataField struct {
+	Label string
+	Value string
+}
+
+// findingDataRenderer summarizes the decoded finding.Data of a data source.
+// It returns nil if the data does not have the expected shape.
+type findingDataRenderer func(data any) *findingDataSummary
+
+// findingDataRenderers is the registry of the renderers keyed by data source and plugin (e.g. aws:guard-duty).
+// The data of the other plugins is rendered as pretty JSON.
+var findingDataRenderers = map[string]findingDataRenderer{
+	"aws:guard-duty": fieldRenderer(
+		[]dataPath{{"Title", []string{"title"}}, {"Type", []string{"type"}}},
+		[]dataPath{
+			{"Account", []string{"account_id"}},
+			{"Region", []string{"region"}},
+			{"Resource type", []string{"resource.resource_type"}},
+			{"Instance", []string{"resource.instance_details.instance_id"}},
+			{"Access key user", []string{"resource.access_key_details.user_name"}},
+		},
+		[]dataPath{
+			{"Description", []string{"description"}},
+			{"Severity", []string{"severity"}},
+			{"Action", []string{"service.action.action_type"}},
+			{"Count", []string{"service.count"}},
+		},
+	),
+	"aws:access-analyzer": fieldRenderer(
+		[]dataPath{{"Resource type", []string{"resource_type"}}, {"Action", []string{"action"}}},
+		[]dataPath{{"Resource", []string{"resource"}}, {"Account", []string{"resource_owner_account"}}, {"Region", []string{"region"}}},
+		[]dataPath{{"Principal", []string{"principal"}}, {"Condition", []string{"condition"}}, {"Public", []string{"is_public"}}, {"Status", []string{"status"}}},
+	),
+	"aws:admin-checker": fieldRenderer(
+		[]dataPath{{"Admin policy", []string{"admin_policy", "enabled_admin_policy"}}},
+		[]dataPath{{"User", []string{"user_arn", "user_name"}}, {"Role", []string{"role_arn", "role_name"}}},
+		[]dataPath{{"Permission boundary", []string{"enabled_permission_boundary"}}, {"Last used", []string{"last_used", "access_key_last_used"}}},
+	),
+	"aws:cloudsploit":    cloudsploitRenderer,
+	"google:cloudsploit": cloudsploitRenderer,
+	"aws:portscan":       portscanRenderer,
+	"google:portscan":    portscanRenderer,
+	"google:asset": fieldRenderer(
+		[]dataPath{{"Asset type", []string{"asset.asset_type", "asset_type"}}},
+		[]dataPath{{"Asset", []string{"asset.name", "name"}}},
+		[]dataPath{{"IAM policy", []string{"asset.iam_policy.bindings", "iam_policy.bindings"}}, {"Public", []string{"is_public"}}},
+	),
+	"google:scc": fieldRenderer(
+		[]dataPath{{"Category", []string{"finding.category", "category"}}},
+		[]dataPath{{"Resource", []string{"finding.resource_name", "resource_name"}}},
+		[]dataPath{{"Description", []string{"finding.description", "description"}}, {"Severity", []string{"finding.severity", "severity"}}, {"State", []string{"finding.state", "state"}}},
+	),
+	"code:gitleaks": fieldRenderer(
+		// The secret itself is not rendered.
+		[]dataPath{{"Rule", []string{"rule_id", "rule"}}, {"Description", []string{"description"}}},
+		[]dataPath{
+			{"Repository", []string{"repository_metadata.full_name", "repository"}},
+			{"File", []string{"file"}},
+			{"Line", []string{"start_line"}},
+			{"Commit", []string{"commit"}},
+		},
+		[]dataPath{{"Author", []string{"author"}}, {"Date", []string{"date"}}, {"URL", []string{"url"}}},
+	),
+	"code:dependency": fieldRenderer(
+		[]dataPath{{"Vulnerability", []string{"vulnerability_id"}}, {"Title", []string{"title"}}},
+		[]dataPath{{"Package", []string{"pkg_name", "package"}}, {"Installed version", []string{"installed_version"}}, {"Target", []string{"target"}}},
+		[]dataPath{{"Severity", []string{"severity"}}, {"Fixed version", []string{"fixed_version"}}, {"Reference", []string{"primary_url"}}},
+	),
+	"code:codescan": fieldRenderer(
+		[]dataPath{{"Rule", []string{"check_id"}}},
+		[]dataPath{{"Repository", []string{"repository"}}, {"File", []string{"path"}}, {"Line", []string{"start.line"}}},
+		[]dataPath{{"Message", []string{"extra.message", "message"}}, {"Severity", []string{"extra.severity", "severity"}}},
+	),
+	"osint:subdomain": fieldRenderer(
+		[]dataPath{{"Takeover", []string{"is_takeover"}}, {"Private IP", []string{"is_private"}}},
+		[]dataPath{{"Host", []string{"host", "domain"}}, {"IP", []string{"ip", "ip_address"}}, {"URL", []string{"url"}}},
+		[]dataPath{{"CNAME", []string{"cname"}}, {"Status", []string{"status"}}},
+	),
+	"osint:website": fieldRenderer(
+		[]dataPath{{"Technologies", []string{"technologies"}}},
+		[]dataPath{{"URL", []string{"url"}}},
+		[]dataPath{{"Version", []string{"version"}}},
+	),
+	"diagnosis:wpscan": fieldRenderer(
+		[]dataPath{{"WordPress version", []string{"version.number", "version"}}},
+		[]dataPath{{"URL", []string{"target_url", "url"}}},
+		[]dataPath{{"Status", []string{"version.status", "status"}}, {"Vulnerabilities", []string{"version.vulnerabilities", "vulnerabilities"}}},
+	),
+	"diagnosis:application-scan": fieldRenderer(
+		[]dataPath{{"Alert", []string{"alert", "name"}}},
+		[]dataPath{{"URL", []string{"url", "uri"}}, {"Parameter", []string{"param"}}},
+		[]dataPath{{"Risk", []string{"risk", "riskdesc"}}, {"Description", []string{"description", "desc"}}, {"Solution", []string{"solution"}}},
+	),
+	"azure:prowler": fieldRenderer(
+		[]dataPath{{"Check", []string{"check_title", "check_id"}}},
+		[]dataPath{{"Resource", []string{"resource_id", "resource_name"}}, {"Subscription", []string{"subscription"}}, {"Region", []string{"region", "location"}}},
+		[]dataPath{{"Status", []string{"status_extended", "status"}}, {"Severity", []string{"severity"}}},
+	),
+}
+
+var cloudsploitRenderer = fieldRenderer(
+	[]dataPath{{"Category", []string{"category"}}, {"Plugin", []string{"plugin"}}, {"Title", []string{"title"}}},
+	[]dataPath{{"Resource", []string{"resource"}}, {"Region", []string{"region"}}},
+	[]dataPath{{"Message", []string{"message"}}, {"Status", []string{"status"}}, {"Description", []string{"description"}}},
+)
+
+var portscanRenderer = fieldRenderer(
+	[]dataPath{{"Protocol", []string{"protocol"}}, {"Port", []string{"port", "from_port"}}, {"Service", []string{"service"}}},
+	[]dataPath{{"Target", []string{"target", "host"}}, {"Resource", []string{"resource_name"}}},
+	[]dataPath{{"Status", []string{"status"}}, {"Security group", []string{"security_group"}}},
+)
+
+// dataPath is a labeled field of finding.Data. The first existing path is used.
+// A path is the dot separated keys, matched regardless of the case, "_" and "-" (e.g. rule_id matches RuleID).
+type dataPath struct {
+	label string
+	paths []string
+}
+
+// fieldRenderer returns the renderer that picks the fields of what, where and why.
+func fieldRenderer(what, where, why []dataPath) findingDataRenderer {
+	return func(data any) *findingDataSummary {
+		summary := &findingDataSummary{
+			What:  pickDataFields(data, what),
+			Where: pickDataFields(data, where),
+			Why:   pickDataFields(data, why),
+		}
+		if summary.empty() {
+			return nil
+		}
+		return summary
+	}
+}
+
+func pickDataFields(data any, fields []dataPath) []*findingDataField {
+	picked := []*findingDataField{}
+	for _, f := range fields {
+		for _, path := range f.paths {
+			v, ok := lookupDataPath(data, path)
+			if !ok {
+				continue
+			}
+			if value := formatDataValue(v); value != "" {
+				picked = append(picked, &findingDataField{Label: f.label, Value: value})
+				break
+			}
+		}
+	}
+	return picked
+}
+
+func lookupDataPath(data any, path string) (any, bool) {
+	v := data
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		found := false
+		for k, child := range obj {
+			if normalizeDataKey(k) == normalizeDataKey(key) {
+				v, found = child, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func normalizeDataKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
+
+func formatDataValue(v any) string {
+	var value string
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		value = t
+	case float64:
+		value = strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		value = strconv.FormatBool(t)
+	case []any:
+		values := []string{}
+		for _, item := range t {
+			if s := formatDataValue(item); s != "" {
+				values = append(values, s)
+			}
+		}
+		value = strings.Join(values, ", ")
+	default:
+		data, err := json.Marshal(t)
+		if err != nil {
+			return ""
+		}
+		value = string(data)
+	}
+	value = strings.Join(strings.Fields(value), " ")
+	if runes := []rune(value); len(runes) > maxFindingDataValueLength {
+		value = string(runes[:maxFindingDataValueLength]) + "..."
+	}
+	return value
+}
+
+// renderFindingData renders finding.Data as Markdown with the renderer of the data source and plugin.
+// The data of the unknown plugins, or the data that the renderer cannot read, is rendered as pretty JSON.
+func renderFindingData(f *finding.Finding) string {
+	if f.Data == "" {
+		return ""
+	}
+	var data any
+	if err := json.Unmarshal([]byte(f.Data), &data); err != nil {
+		return fmt.Sprintf("```\n%s\n```", f.Data)
+	}
+	if renderer, ok := findingDataRenderers[f.DataSource]; ok {
+		if summary := renderer(data); summary != nil {
+			return renderFindingDataSummary(summary)
+		}
+	}
+	pretty, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("```\n%s\n```", f.Data)
+	}
+	return fmt.Sprintf("```json\n%s\n```", pretty)
+}
+
+func renderFindingDataSummary(summary *findingDataSummary) string {
+	var b strings.Builder
+	for _, section := range []struct {
+		title  string
+		fields []*findingDataField
+	}{
+		{"What", summary.What},
+		{"Where", summary.Where},
+		{"Why", summary.Why},
+	} {
+		if len(section.fields) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "**%s**\n", section.title)
+		for _, f := range section.fields {
+			fmt.Fprintf(&b, "- %s: %s\n", f.Label, f.Value)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderFindingMarkdown renders the finding with the summary of its data as Markdown.
+func renderFindingMarkdown(f *finding.Finding) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Finding %d: %s\n\n", f.FindingId, f.Description)
+	fmt.Fprintf(&b, "- Data source: %s\n", f.DataSource)
+	fmt.Fprintf(&b, "- Resource: %s\n", f.ResourceName)
+	fmt.Fprintf(&b, "- Score: %s\n", strconv.FormatFloat(float64(f.Score), 'f', -1, 32))
+	if f.UpdatedAt > 0 {
+		fmt.Fprintf(&b, "- Updated at: %s\n", time.Unix(f.UpdatedAt, 0).UTC().Format(time.RFC3339))
+	}
+	if data := renderFindingData(f); data != "" {
+		fmt.Fprintf(&b, "\n### Data\n\n%s\n", data)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
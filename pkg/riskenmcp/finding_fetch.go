@@ -0,0 +1,76 @@
+package riskenmcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/ca-risken/go-risken"
+)
+
+// FindingError describes a finding that could not be fetched.
+type FindingError struct {
+	FindingID uint64 `json:"finding_id"`
+	Error     string `json:"error"`
+}
+
+// fetchFindings gets the findings with a bounded worker pool.
+// The returned findings keep the order of findingIDs, and failed lookups are reported as FindingError instead of aborting the whole fetch.
+func (s *Server) fetchFindings(ctx context.Context, riskenClient *risken.Client, projectID uint32, findingIDs []uint64) ([]*finding.Finding, []*FindingError) {
+	findings := make([]*finding.Finding, len(findingIDs))
+	errs := make([]error, len(findingIDs))
+
+	s.runParallel(len(findingIDs), func(i int) {
+		findings[i], errs[i] = getFinding(ctx, riskenClient, projectID, findingIDs[i])
+	})
+
+	fetched := []*finding.Finding{}
+	failed := []*FindingError{}
+	for i, f := range findings {
+		if errs[i] != nil {
+			failed = append(failed, &FindingError{FindingID: findingIDs[i], Error: errs[i].Error()})
+			continue
+		}
+		fetched = append(fetched, f)
+	}
+	return fetched, failed
+}
+
+// runParallel calls fn for each index in [0, n) with at most Config.FindingFetchConcurrency goroutines.
+func (s *Server) runParallel(n int, fn func(i int)) {
+	workers := min(s.config.FindingFetchConcurrency, n)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+func getFinding(ctx context.Context, riskenClient *risken.Client, projectID uint32, findingID uint64) (*finding.Finding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	resp, err := riskenClient.GetFinding(ctx, &finding.GetFindingRequest{
+		ProjectId: projectID,
+		FindingId: findingID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Finding == nil {
+		return nil, errors.New("finding not found")
+	}
+	return resp.Finding, nil
+}
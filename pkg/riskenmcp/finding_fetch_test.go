@@ -0,0 +1,119 @@
+package riskenmcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ca-risken/core/proto/project"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestRISKENClient returns a RISKEN client connected to a fake API server.
+func newTestRISKENClient(t *testing.T, handler http.HandlerFunc) *risken.Client {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return risken.NewClient("test-token", risken.WithAPIEndpoint(ts.URL))
+}
+
+func newTestServer(client *risken.Client, config *Config) *Server {
+	s := &Server{
+		riskenClient: client,
+		config:       config.withDefaults(),
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	s.projectCache = helper.NewTTLCache[*project.Project](s.config.ProjectCacheTTL, defaultProjectCacheSize)
+	s.signer = newTokenSigner(s.config.SigningKey)
+	s.bulkArchivePlans = helper.NewTTLCache[*bulkArchivePlan](bulkArchiveTokenTTL, bulkArchiveMaxPlans)
+	return s
+}
+
+func TestFetchFindings(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		findingIDs  []uint64
+		failIDs     map[uint64]bool
+		wantIDs     []uint64
+		wantErrIDs  []uint64
+	}{
+		{
+			name:        "keep order",
+			concurrency: 3,
+			findingIDs:  []uint64{5, 4, 3, 2, 1},
+			wantIDs:     []uint64{5, 4, 3, 2, 1},
+			wantErrIDs:  []uint64{},
+		},
+		{
+			name:        "partial failure",
+			concurrency: 2,
+			findingIDs:  []uint64{1, 2, 3, 4},
+			failIDs:     map[uint64]bool{2: true, 4: true},
+			wantIDs:     []uint64{1, 3},
+			wantErrIDs:  []uint64{2, 4},
+		},
+		{
+			name:        "empty",
+			concurrency: 2,
+			findingIDs:  []uint64{},
+			wantIDs:     []uint64{},
+			wantErrIDs:  []uint64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, maxRunning int32
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				id, _ := strconv.ParseUint(r.URL.Query().Get("finding_id"), 10, 64)
+				if tt.failIDs[id] {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"data":{"finding":{"finding_id":%d,"project_id":1}}}`, id)
+			})
+			s := newTestServer(client, &Config{FindingFetchConcurrency: tt.concurrency})
+
+			findings, errs := s.fetchFindings(context.Background(), client, 1, tt.findingIDs)
+
+			gotIDs := []uint64{}
+			for _, f := range findings {
+				gotIDs = append(gotIDs, f.FindingId)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Errorf("fetchFindings() findings mismatch (-want +got):\n%s", diff)
+			}
+			gotErrIDs := []uint64{}
+			for _, e := range errs {
+				gotErrIDs = append(gotErrIDs, e.FindingID)
+			}
+			if diff := cmp.Diff(tt.wantErrIDs, gotErrIDs); diff != "" {
+				t.Errorf("fetchFindings() errors mismatch (-want +got):\n%s", diff)
+			}
+			if int(maxRunning) > tt.concurrency {
+				t.Errorf("fetchFindings() concurrency = %d, want <= %d", maxRunning, tt.concurrency)
+			}
+		})
+	}
+}
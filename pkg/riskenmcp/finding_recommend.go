@@ -0,0 +1,166 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// FindingRecommendation is the remediation guide of a finding.
+type FindingRecommendation struct {
+	FindingID      uint64   `json:"finding_id"`
+	DataSource     string   `json:"data_source,omitempty"`
+	Type           string   `json:"type,omitempty"`
+	Risk           string   `json:"risk,omitempty"`
+	Recommendation string   `json:"recommendation,omitempty"`
+	References     []string `json:"references,omitempty"`
+}
+
+func (s *Server) GetFindingRecommendation() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_finding_recommendation",
+			mcp.WithDescription("Get the risk description and recommendation (how to fix) of RISKEN finding. Use this when a request include \"how to fix\", \"recommendation\", \"remediation\", \"対応方法\", \"修正方法\"..."),
+			mcp.WithNumber(
+				"finding_id",
+				mcp.Description("Finding ID."),
+				mcp.Required(),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			params, err := s.ParseGetRecommendParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			recommend, err := s.getFindingRecommendation(ctx, riskenClient, params)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get recommendation: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(recommend)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal recommendation: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) ParseGetRecommendParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*finding.GetRecommendRequest, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	param := &finding.GetRecommendRequest{
+		ProjectId: p.ProjectId,
+	}
+
+	findingID, err := helper.ParseMCPArgs[float64]("finding_id", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("finding_id error: %s", err)
+	}
+	if findingID == nil {
+		return nil, errors.New("finding_id is required")
+	}
+	param.FindingId = uint64(*findingID)
+	return param, nil
+}
+
+func (s *Server) GetFindingRecommendationResource() (mcp.ResourceTemplate, server.ResourceTemplateHandlerFunc) {
+	return mcp.NewResourceTemplate(
+			"finding://{project_id}/{finding_id}/recommendation",
+			"RISKEN Finding Recommendation",
+		),
+		s.FindingRecommendationResourceContentsHandler()
+}
+
+func (s *Server) FindingRecommendationResourceContentsHandler() func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		riskenClient, err := s.GetRISKENClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+		}
+
+		p, err := s.getResourceProject(ctx, riskenClient, request)
+		if err != nil {
+			return nil, err
+		}
+		findingID, err := parseResourceID("finding_id", request)
+		if err != nil {
+			return nil, err
+		}
+
+		// Call RISKEN API
+		recommend, err := s.getFindingRecommendation(ctx, riskenClient, &finding.GetRecommendRequest{
+			ProjectId: p.ProjectId,
+			FindingId: findingID,
+		})
+		if err != nil {
+			s.invalidateOnAuthError(ctx, riskenClient, err)
+			return nil, errors.New("failed to get recommendation")
+		}
+		jsonData, err := json.Marshal(recommend)
+		if err != nil {
+			return nil, errors.New("failed to marshal recommendation")
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		}, nil
+	}
+}
+
+func (s *Server) getFindingRecommendation(ctx context.Context, riskenClient *risken.Client, params *finding.GetRecommendRequest) (*FindingRecommendation, error) {
+	resp, err := riskenClient.GetRecommend(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Recommend == nil {
+		return nil, fmt.Errorf("recommendation not found: finding_id=%d", params.FindingId)
+	}
+	return &FindingRecommendation{
+		FindingID:      params.FindingId,
+		DataSource:     resp.Recommend.DataSource,
+		Type:           resp.Recommend.Type,
+		Risk:           resp.Recommend.Risk,
+		Recommendation: resp.Recommend.Recommendation,
+		References:     extractURLs(resp.Recommend.Risk, resp.Recommend.Recommendation),
+	}, nil
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+
+// extractURLs returns the unique URLs in the texts in order of appearance.
+func extractURLs(texts ...string) []string {
+	urls := []string{}
+	seen := map[string]bool{}
+	for _, text := range texts {
+		for _, u := range urlPattern.FindAllString(text, -1) {
+			u = strings.TrimRight(u, ".,;:!?`*")
+			if seen[u] {
+				continue
+			}
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,221 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ca-risken/go-risken"
+	"github.com/google/go-cmp/cmp"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestExtractURLs(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  []string
+	}{
+		{
+			name: "markdown and plain text",
+			texts: []string{
+				"See https://docs.aws.amazon.com/guardduty/latest/ug/what-is-guardduty.html.",
+				"- [Doc](https://example.com/a?b=c)\n- https://docs.aws.amazon.com/guardduty/latest/ug/what-is-guardduty.html",
+			},
+			want: []string{
+				"https://docs.aws.amazon.com/guardduty/latest/ug/what-is-guardduty.html",
+				"https://example.com/a?b=c",
+			},
+		},
+		{
+			name:  "no url",
+			texts: []string{"Disable the public access.", ""},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractURLs(tt.texts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("extractURLs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func newTestRecommendClient(t *testing.T, getHit *bool) *risken.Client {
+	t.Helper()
+	var signinCount int32
+	projectHandler := newTestProjectHandler(&signinCount)
+	return newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/finding/get-recommend" {
+			projectHandler(w, r)
+			return
+		}
+		*getHit = true
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("finding_id") {
+		case "10":
+			_, _ = w.Write([]byte(`{"data":{"recommend":{"data_source":"aws:guard-duty","type":"Recon","risk":"See https://example.com/risk.","recommendation":"Fix it."}}}`))
+		case "20":
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
+
+func TestGetFindingRecommendation(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       map[string]any
+		want       *FindingRecommendation
+		wantErr    string
+		wantGetHit bool
+	}{
+		{
+			name: "success",
+			args: map[string]any{"finding_id": float64(10)},
+			want: &FindingRecommendation{
+				FindingID:      10,
+				DataSource:     "aws:guard-duty",
+				Type:           "Recon",
+				Risk:           "See https://example.com/risk.",
+				Recommendation: "Fix it.",
+				References:     []string{"https://example.com/risk"},
+			},
+			wantGetHit: true,
+		},
+		{
+			name:       "recommendation not found",
+			args:       map[string]any{"finding_id": float64(20)},
+			wantErr:    "recommendation not found: finding_id=20",
+			wantGetHit: true,
+		},
+		{
+			name:       "api error",
+			args:       map[string]any{"finding_id": float64(30)},
+			wantErr:    "failed to get recommendation",
+			wantGetHit: true,
+		},
+		{
+			name:    "no finding_id",
+			args:    map[string]any{},
+			wantErr: "finding_id is required",
+		},
+		{
+			name:    "invalid finding_id type",
+			args:    map[string]any{"finding_id": "10"},
+			wantErr: "finding_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getHit := false
+			s := newTestServer(newTestRecommendClient(t, &getHit), nil)
+			_, handler := s.GetFindingRecommendation()
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handler() error = %v", err)
+			}
+			text := result.Content[0].(mcp.TextContent).Text
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Errorf("handler() = %q, want error %q", text, tt.wantErr)
+				}
+			} else {
+				if result.IsError {
+					t.Fatalf("handler() returned error: %s", text)
+				}
+				got := &FindingRecommendation{}
+				if err := json.Unmarshal([]byte(text), got); err != nil {
+					t.Fatalf("failed to unmarshal result: %v", err)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("handler() mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if getHit != tt.wantGetHit {
+				t.Errorf("GetRecommend called = %v, want %v", getHit, tt.wantGetHit)
+			}
+		})
+	}
+}
+
+func TestFindingRecommendationResourceContentsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       map[string]any
+		wantErr    string
+		wantGetHit bool
+	}{
+		{
+			name:       "uri template arguments",
+			args:       map[string]any{"project_id": []string{"1"}, "finding_id": []string{"10"}},
+			wantGetHit: true,
+		},
+		{
+			name:       "string arguments",
+			args:       map[string]any{"project_id": "1", "finding_id": "10"},
+			wantGetHit: true,
+		},
+		{
+			name:    "invalid finding_id",
+			args:    map[string]any{"project_id": []string{"1"}, "finding_id": []string{"abc"}},
+			wantErr: "invalid finding_id: abc",
+		},
+		{
+			name:    "no finding_id",
+			args:    map[string]any{"project_id": []string{"1"}},
+			wantErr: "finding_id is required",
+		},
+		{
+			name:    "another project",
+			args:    map[string]any{"project_id": []string{"999"}, "finding_id": []string{"10"}},
+			wantErr: "project_id=999 in the URI is not the authenticated project (project_id=1)",
+		},
+		{
+			name:       "recommendation not found",
+			args:       map[string]any{"project_id": []string{"1"}, "finding_id": []string{"20"}},
+			wantErr:    "failed to get recommendation",
+			wantGetHit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getHit := false
+			s := newTestServer(newTestRecommendClient(t, &getHit), nil)
+
+			req := mcp.ReadResourceRequest{}
+			req.Params.URI = "finding://1/10/recommendation"
+			req.Params.Arguments = tt.args
+			contents, err := s.FindingRecommendationResourceContentsHandler()(context.Background(), req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("handler() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("handler() error = %v", err)
+			} else {
+				if len(contents) != 1 {
+					t.Fatalf("handler() returned %d contents, want 1", len(contents))
+				}
+				text := contents[0].(mcp.TextResourceContents).Text
+				if !strings.Contains(text, `"finding_id":10`) {
+					t.Errorf("handler() contents = %s, want finding_id=10", text)
+				}
+			}
+			if getHit != tt.wantGetHit {
+				t.Errorf("GetRecommend called = %v, want %v", getHit, tt.wantGetHit)
+			}
+		})
+	}
+}
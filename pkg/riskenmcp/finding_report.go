@@ -0,0 +1,343 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ca-risken/core/proto/report"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	reportIntervalDay  = "day"
+	reportIntervalWeek = "week"
+
+	reportDateFormat = "2006-01-02"
+
+	defaultReportDays = 30
+	// maxReportDays is the retention of the RISKEN finding report.
+	maxReportDays = 365
+)
+
+// Trend directions
+const (
+	trendUp   = "up"
+	trendDown = "down"
+	trendFlat = "flat"
+)
+
+// FindingReport is the time series of the finding counts.
+type FindingReport struct {
+	Interval string                   `json:"interval"`
+	FromDate string                   `json:"from_date"`
+	ToDate   string                   `json:"to_date"`
+	Points   []*FindingReportPoint    `json:"points"`
+	Trend    map[string]*FindingTrend `json:"trend,omitempty"`
+}
+
+// FindingReportPoint is the finding counts on a day, or the last reported day of a week.
+type FindingReportPoint struct {
+	Date         string                     `json:"date"`
+	ReportDate   string                     `json:"report_date"`
+	ByScore      *ScoreBandCount            `json:"by_score"`
+	ByDataSource map[string]*ScoreBandCount `json:"by_data_source"`
+}
+
+// FindingTrend is the change of the count between the first and last points.
+type FindingTrend struct {
+	Start        uint32   `json:"start"`
+	End          uint32   `json:"end"`
+	Delta        int64    `json:"delta"`
+	DeltaPercent *float64 `json:"delta_percent,omitempty"`
+	Direction    string   `json:"direction"`
+}
+
+type findingReportParams struct {
+	ProjectID  uint32
+	From       time.Time
+	To         time.Time
+	Interval   string
+	DataSource []string
+	FromScore  float32
+	Trend      bool
+}
+
+func (s *Server) FindingReport() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("finding_report",
+			mcp.WithDescription("Get the time series of the active RISKEN finding counts per day or per week, by data source and score band (low: 0.0~0.3, medium: 0.3~0.6, high: 0.6~0.8, critical: 0.8~1.0). "+
+				"Also returns the delta and trend direction between the first and last points. "+
+				"Use this when a request include \"trend\", \"going down\", \"report\", \"this quarter\", \"推移\", \"傾向\", \"レポート\"..."),
+			mcp.WithString(
+				"from_date",
+				mcp.Description(fmt.Sprintf("Start date of the report (inclusive). Format: YYYY-MM-DD. (default: %d days ago, max: %d days ago)", defaultReportDays, maxReportDays)),
+			),
+			mcp.WithString(
+				"to_date",
+				mcp.Description("End date of the report (inclusive). Format: YYYY-MM-DD. (default: today)"),
+			),
+			mcp.WithString(
+				"interval",
+				mcp.Description("Interval of the points. The weekly point is the last reported day of the week (weeks start on Monday)."),
+				mcp.Enum(reportIntervalDay, reportIntervalWeek),
+				mcp.DefaultString(reportIntervalDay),
+			),
+			mcp.WithArray(
+				"data_source",
+				mcp.Description("RISKEN DataSource. e.g. aws, google, code (like github, gitlab, etc.), osint, diagnosis, azure, ..."),
+				mcp.Enum(findingDataSources...),
+			),
+			mcp.WithNumber(
+				"from_score",
+				mcp.Description("Minimum score of the findings."),
+				mcp.DefaultNumber(0),
+				mcp.Min(0),
+				mcp.Max(1),
+			),
+			mcp.WithBoolean(
+				"trend",
+				mcp.Description("Return the delta and trend direction of the total and each score band."),
+				mcp.DefaultBool(true),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			params, err := s.ParseFindingReportParams(ctx, req, riskenClient, time.Now().UTC())
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			// data_source is the prefix of the report records (e.g. aws of aws:guard-duty), so it is filtered in buildFindingReport.
+			resp, err := riskenClient.GetReportFinding(ctx, &report.GetReportFindingRequest{
+				ProjectId: params.ProjectID,
+				FromDate:  params.From.Format(reportDateFormat),
+				ToDate:    params.To.Format(reportDateFormat),
+				Score:     params.FromScore,
+			})
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get finding report: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(buildFindingReport(params, resp.ReportFinding))
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) ParseFindingReportParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client, now time.Time) (*findingReportParams, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	today := now.Truncate(24 * time.Hour)
+	params := &findingReportParams{
+		ProjectID: p.ProjectId,
+		From:      today.AddDate(0, 0, -defaultReportDays),
+		To:        today,
+		Interval:  reportIntervalDay,
+		Trend:     true,
+	}
+
+	fromDate, err := helper.ParseMCPArgs[string]("from_date", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("from_date error: %s", err)
+	}
+	if fromDate != nil {
+		params.From, err = helper.ParseISO8601Time(*fromDate)
+		if err != nil {
+			return nil, fmt.Errorf("from_date error: %s", err)
+		}
+	}
+	toDate, err := helper.ParseMCPArgs[string]("to_date", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("to_date error: %s", err)
+	}
+	if toDate != nil {
+		params.To, err = helper.ParseISO8601Time(*toDate)
+		if err != nil {
+			return nil, fmt.Errorf("to_date error: %s", err)
+		}
+	}
+	params.From = params.From.Truncate(24 * time.Hour)
+	params.To = params.To.Truncate(24 * time.Hour)
+	if params.From.After(params.To) {
+		return nil, fmt.Errorf("from_date(%s) must be before to_date(%s)", params.From.Format(reportDateFormat), params.To.Format(reportDateFormat))
+	}
+	if params.From.Before(today.AddDate(0, 0, -maxReportDays)) {
+		return nil, fmt.Errorf("from_date must be within %d days, the retention of the report", maxReportDays)
+	}
+
+	interval, err := helper.ParseMCPArgs[string]("interval", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("interval error: %s", err)
+	}
+	if interval != nil {
+		if *interval != reportIntervalDay && *interval != reportIntervalWeek {
+			return nil, fmt.Errorf("interval must be %s or %s", reportIntervalDay, reportIntervalWeek)
+		}
+		params.Interval = *interval
+	}
+	dataSource, err := helper.ParseMCPArgs[[]any]("data_source", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("data_source error: %s", err)
+	}
+	if dataSource != nil {
+		for _, v := range *dataSource {
+			params.DataSource = append(params.DataSource, fmt.Sprintf("%v", v))
+		}
+	}
+	fromScore, err := helper.ParseMCPArgs[float64]("from_score", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("from_score error: %s", err)
+	}
+	if fromScore != nil {
+		if *fromScore < 0 || *fromScore > 1 {
+			return nil, errors.New("from_score must be between 0.0 and 1.0")
+		}
+		params.FromScore = float32(*fromScore)
+	}
+	trend, err := helper.ParseMCPArgs[bool]("trend", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("trend error: %s", err)
+	}
+	if trend != nil {
+		params.Trend = *trend
+	}
+	return params, nil
+}
+
+// buildFindingReport aggregates the report records by date, data source and score band.
+// A report record is the daily snapshot of the finding count per data source and score,
+// so the weekly point uses the last reported day of the week instead of the sum of the days.
+// The days without any records are not included in the points.
+// The records are filtered by params.DataSource with the data source prefix before ":".
+func buildFindingReport(params *findingReportParams, records []*report.ReportFinding) *FindingReport {
+	days := map[string]*FindingReportPoint{}
+	for _, r := range records {
+		dataSource, _, _ := strings.Cut(r.DataSource, ":")
+		if len(params.DataSource) > 0 && !slices.Contains(params.DataSource, dataSource) {
+			continue
+		}
+		date := r.ReportDate
+		if len(date) > len(reportDateFormat) {
+			date = date[:len(reportDateFormat)]
+		}
+		point, ok := days[date]
+		if !ok {
+			point = &FindingReportPoint{
+				Date:         date,
+				ReportDate:   date,
+				ByScore:      &ScoreBandCount{},
+				ByDataSource: map[string]*ScoreBandCount{},
+			}
+			days[date] = point
+		}
+		counts, ok := point.ByDataSource[dataSource]
+		if !ok {
+			counts = &ScoreBandCount{}
+			point.ByDataSource[dataSource] = counts
+		}
+		counts.add(r.Score, r.Count)
+		point.ByScore.add(r.Score, r.Count)
+	}
+
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	points := []*FindingReportPoint{}
+	for _, date := range dates {
+		point := days[date]
+		if params.Interval == reportIntervalWeek {
+			point.Date = weekStart(date)
+			if len(points) > 0 && points[len(points)-1].Date == point.Date {
+				// Overwrite with the later day of the same week.
+				points[len(points)-1] = point
+				continue
+			}
+		}
+		points = append(points, point)
+	}
+
+	result := &FindingReport{
+		Interval: params.Interval,
+		FromDate: params.From.Format(reportDateFormat),
+		ToDate:   params.To.Format(reportDateFormat),
+		Points:   points,
+	}
+	if params.Trend && len(points) > 0 {
+		first, last := points[0].ByScore, points[len(points)-1].ByScore
+		result.Trend = map[string]*FindingTrend{
+			"total":    newFindingTrend(first.Total, last.Total),
+			"low":      newFindingTrend(first.Low, last.Low),
+			"medium":   newFindingTrend(first.Medium, last.Medium),
+			"high":     newFindingTrend(first.High, last.High),
+			"critical": newFindingTrend(first.Critical, last.Critical),
+		}
+	}
+	return result
+}
+
+// add counts the findings into the score band.
+func (c *ScoreBandCount) add(score float32, count uint32) {
+	c.Total += count
+	switch {
+	case score >= scoreBandCriticalFrom:
+		c.Critical += count
+	case score >= scoreBandHighFrom:
+		c.High += count
+	case score >= scoreBandMediumFrom:
+		c.Medium += count
+	default:
+		c.Low += count
+	}
+}
+
+// weekStart returns the Monday of the week of the date.
+func weekStart(date string) string {
+	t, err := time.Parse(reportDateFormat, date)
+	if err != nil {
+		return date
+	}
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset).Format(reportDateFormat)
+}
+
+func newFindingTrend(start, end uint32) *FindingTrend {
+	trend := &FindingTrend{
+		Start:     start,
+		End:       end,
+		Delta:     int64(end) - int64(start),
+		Direction: trendFlat,
+	}
+	if trend.Delta > 0 {
+		trend.Direction = trendUp
+	} else if trend.Delta < 0 {
+		trend.Direction = trendDown
+	}
+	if start > 0 {
+		// Round to 2 decimal places.
+		percent := math.Round(float64(trend.Delta)*10000/float64(start)) / 100
+		trend.DeltaPercent = &percent
+	}
+	return trend
+}
@@ -0,0 +1,194 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ca-risken/core/proto/report"
+	"github.com/google/go-cmp/cmp"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBuildFindingReport(t *testing.T) {
+	records := []*report.ReportFinding{
+		{ReportDate: "2025-06-02", DataSource: "aws:guard-duty", Score: 0.9, Count: 4},
+		{ReportDate: "2025-06-02", DataSource: "aws:access-analyzer", Score: 0.5, Count: 2},
+		{ReportDate: "2025-06-02", DataSource: "google:scc", Score: 0.1, Count: 3},
+		{ReportDate: "2025-06-03", DataSource: "aws:guard-duty", Score: 0.9, Count: 2},
+		{ReportDate: "2025-06-09T00:00:00Z", DataSource: "aws:guard-duty", Score: 0.7, Count: 1},
+	}
+	tests := []struct {
+		name       string
+		interval   string
+		dataSource []string
+		trend      bool
+		want       *FindingReport
+	}{
+		{
+			name:     "daily with trend",
+			interval: reportIntervalDay,
+			trend:    true,
+			want: &FindingReport{
+				Interval: reportIntervalDay,
+				FromDate: "2025-06-01",
+				ToDate:   "2025-06-10",
+				Points: []*FindingReportPoint{
+					{
+						Date:       "2025-06-02",
+						ReportDate: "2025-06-02",
+						ByScore:    &ScoreBandCount{Total: 9, Low: 3, Medium: 2, Critical: 4},
+						ByDataSource: map[string]*ScoreBandCount{
+							"aws":    {Total: 6, Medium: 2, Critical: 4},
+							"google": {Total: 3, Low: 3},
+						},
+					},
+					{
+						Date:         "2025-06-03",
+						ReportDate:   "2025-06-03",
+						ByScore:      &ScoreBandCount{Total: 2, Critical: 2},
+						ByDataSource: map[string]*ScoreBandCount{"aws": {Total: 2, Critical: 2}},
+					},
+					{
+						Date:         "2025-06-09",
+						ReportDate:   "2025-06-09",
+						ByScore:      &ScoreBandCount{Total: 1, High: 1},
+						ByDataSource: map[string]*ScoreBandCount{"aws": {Total: 1, High: 1}},
+					},
+				},
+				Trend: map[string]*FindingTrend{
+					"total":    {Start: 9, End: 1, Delta: -8, DeltaPercent: ptr(-88.89), Direction: trendDown},
+					"low":      {Start: 3, End: 0, Delta: -3, DeltaPercent: ptr(-100.0), Direction: trendDown},
+					"medium":   {Start: 2, End: 0, Delta: -2, DeltaPercent: ptr(-100.0), Direction: trendDown},
+					"high":     {Start: 0, End: 1, Delta: 1, Direction: trendUp},
+					"critical": {Start: 4, End: 0, Delta: -4, DeltaPercent: ptr(-100.0), Direction: trendDown},
+				},
+			},
+		},
+		{
+			name:       "data source filter",
+			interval:   reportIntervalDay,
+			dataSource: []string{"google"},
+			want: &FindingReport{
+				Interval: reportIntervalDay,
+				FromDate: "2025-06-01",
+				ToDate:   "2025-06-10",
+				Points: []*FindingReportPoint{
+					{
+						Date:         "2025-06-02",
+						ReportDate:   "2025-06-02",
+						ByScore:      &ScoreBandCount{Total: 3, Low: 3},
+						ByDataSource: map[string]*ScoreBandCount{"google": {Total: 3, Low: 3}},
+					},
+				},
+			},
+		},
+		{
+			name:     "weekly uses the last day",
+			interval: reportIntervalWeek,
+			want: &FindingReport{
+				Interval: reportIntervalWeek,
+				FromDate: "2025-06-01",
+				ToDate:   "2025-06-10",
+				Points: []*FindingReportPoint{
+					{
+						Date:         "2025-06-02",
+						ReportDate:   "2025-06-03",
+						ByScore:      &ScoreBandCount{Total: 2, Critical: 2},
+						ByDataSource: map[string]*ScoreBandCount{"aws": {Total: 2, Critical: 2}},
+					},
+					{
+						Date:         "2025-06-09",
+						ReportDate:   "2025-06-09",
+						ByScore:      &ScoreBandCount{Total: 1, High: 1},
+						ByDataSource: map[string]*ScoreBandCount{"aws": {Total: 1, High: 1}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &findingReportParams{
+				From:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				To:         time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC),
+				Interval:   tt.interval,
+				DataSource: tt.dataSource,
+				Trend:      tt.trend,
+			}
+			got := buildFindingReport(params, records)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("buildFindingReport() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{date: "2025-06-02", want: "2025-06-02"}, // Monday
+		{date: "2025-06-08", want: "2025-06-02"}, // Sunday
+		{date: "2025-06-05", want: "2025-06-02"},
+		{date: "invalid", want: "invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			if got := weekStart(tt.date); got != tt.want {
+				t.Errorf("weekStart() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestFindingReportDataSource(t *testing.T) {
+	var signinCount int32
+	projectHandler := newTestProjectHandler(&signinCount)
+	today := time.Now().UTC().Format(reportDateFormat)
+	client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/report/get-report" {
+			projectHandler(w, r)
+			return
+		}
+		if got := r.URL.Query()["data_source"]; len(got) != 0 {
+			t.Errorf("get-report data_source = %v, want none", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"report_finding":[` +
+			`{"report_date":"` + today + `","data_source":"aws:guard-duty","score":0.9,"count":4},` +
+			`{"report_date":"` + today + `","data_source":"google:scc","score":0.1,"count":3}]}}`))
+	})
+	s := newTestServer(client, nil)
+	_, handler := s.FindingReport()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"data_source": []any{"aws"}}
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError {
+		t.Fatalf("handler() returned error: %s", text)
+	}
+	got := &FindingReport{}
+	if err := json.Unmarshal([]byte(text), got); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if len(got.Points) != 1 {
+		t.Fatalf("len(points) = %d, want 1", len(got.Points))
+	}
+	want := map[string]*ScoreBandCount{"aws": {Total: 4, Critical: 4}}
+	if diff := cmp.Diff(want, got.Points[0].ByDataSource); diff != "" {
+		t.Errorf("by_data_source mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,539 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	defaultResourceFindingLimit = 10
+	maxResourceFindingLimit     = 100
+	resourceTagListLimit        = 200
+
+	// resourceFindingPageSize and resourceFindingScanLimit bound the scan of the findings of a resource.
+	// resource_name of ListFinding is a prefix match, so the findings of the resources that share the prefix are scanned and dropped.
+	resourceFindingPageSize  = 100
+	resourceFindingScanLimit = 500
+)
+
+type ListResourcesResponse struct {
+	Resources  []*ResourceInfo  `json:"resources"`
+	Errors     []*ResourceError `json:"errors,omitempty"`
+	Total      uint32           `json:"total"`
+	Offset     int32            `json:"offset"`
+	Limit      int32            `json:"limit"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// listResourcesQuery is the query of list_resources encoded in the cursor.
+// The resources are listed in resource ID order, and LastID is the resource ID before the offset to resume after it.
+type listResourcesQuery struct {
+	Request   *finding.ListResourceRequest `json:"request"`
+	FromScore float32                      `json:"from_score,omitempty"`
+	LastID    uint64                       `json:"last_id,omitempty"`
+}
+
+// ResourceInfo is a RISKEN resource with the number of its active findings.
+type ResourceInfo struct {
+	ResourceID   uint64 `json:"resource_id"`
+	ResourceName string `json:"resource_name"`
+	Findings     uint32 `json:"findings"`
+	// FindingsApproximate is true if Findings is an upper bound, because it also counts the findings of the resources sharing the name prefix.
+	FindingsApproximate bool   `json:"findings_approximate,omitempty"`
+	CreatedAt           string `json:"created_at,omitempty"`
+	UpdatedAt           string `json:"updated_at,omitempty"`
+}
+
+// ResourceError describes a resource that could not be fetched.
+type ResourceError struct {
+	ResourceID uint64 `json:"resource_id"`
+	Error      string `json:"error"`
+}
+
+// ResourceDetail is a RISKEN resource with its tags and findings.
+type ResourceDetail struct {
+	ResourceID    uint64             `json:"resource_id"`
+	ResourceName  string             `json:"resource_name"`
+	Tags          []string           `json:"tags"`
+	Findings      []*finding.Finding `json:"findings"`
+	FindingErrors []*FindingError    `json:"finding_errors,omitempty"`
+	// Truncated is true if the scan of the findings stopped at the limit, so some findings of the resource may be missing.
+	Truncated bool   `json:"truncated,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+func (s *Server) ListResources() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_resources",
+			mcp.WithDescription("List RISKEN resources (e.g. AWS IAM user, GitHub repository, domain ...) with the number of active findings. "+
+				"findings is an upper bound if findings_approximate is true. Use this when a request include \"resource\", \"asset\", \"リソース\", \"アセット\"..."),
+			mcp.WithArray(
+				"resource_name",
+				mcp.Description("Prefix of RISKEN ResourceName. e.g. \"arn:aws:iam::123456789012:\", \"github/my-org/\" ..."),
+			),
+			mcp.WithArray(
+				"tag",
+				mcp.Description("Tag of the resources. e.g. \"aws\", \"github\" ..."),
+			),
+			mcp.WithNumber(
+				"from_score",
+				mcp.Description("Only list the resources that have active findings with the score or higher. The filter is applied to each page, so a page may contain fewer resources than limit."),
+				mcp.Max(1.0),
+				mcp.Min(0.0),
+			),
+			mcp.WithNumber(
+				"offset",
+				mcp.Description("Offset of the resources in resource ID order."),
+				mcp.DefaultNumber(0),
+			),
+			mcp.WithNumber(
+				"limit",
+				mcp.Description("Limit of the resources."),
+				mcp.DefaultNumber(10),
+				mcp.Max(100),
+				mcp.Min(1),
+			),
+			withCursor(),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			query, err := s.ParseListResourcesParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+			params := query.Request
+
+			// Call RISKEN API
+			resources, err := riskenClient.ListResource(ctx, params)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list resources: %s", err)), nil
+			}
+			fetched, fetchErrors := s.fetchResources(ctx, riskenClient, params.ProjectId, resources.ResourceId, query.FromScore)
+			result := &ListResourcesResponse{
+				Resources: fetched,
+				Errors:    fetchErrors,
+				Total:     resources.Total,
+				Offset:    params.Offset,
+				Limit:     params.Limit,
+			}
+			result.NextCursor, err = s.nextListResourcesCursor(query, resources)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to issue cursor: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(result)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal resources: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+// ParseListResourcesParams returns the query of the first page, or the query decoded from the cursor.
+func (s *Server) ParseListResourcesParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*listResourcesQuery, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	query := &listResourcesQuery{}
+	ok, err := s.parseCursor(req, "list_resources", p.ProjectId, query)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if err := resumeResourceOffset(ctx, riskenClient, query); err != nil {
+			return nil, fmt.Errorf("failed to resume from cursor: %s", err)
+		}
+		return query, nil
+	}
+	param := &finding.ListResourceRequest{
+		ProjectId: p.ProjectId,
+		// Default params
+		Sort:      "resource_id",
+		Direction: "asc",
+		Offset:    0,
+		Limit:     10,
+	}
+
+	resourceName, err := helper.ParseMCPArgs[[]any]("resource_name", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("resource_name error: %s", err)
+	}
+	if resourceName != nil {
+		for _, v := range *resourceName {
+			param.ResourceName = append(param.ResourceName, fmt.Sprintf("%v", v))
+		}
+	}
+	tag, err := helper.ParseMCPArgs[[]any]("tag", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("tag error: %s", err)
+	}
+	if tag != nil {
+		for _, v := range *tag {
+			param.Tag = append(param.Tag, fmt.Sprintf("%v", v))
+		}
+	}
+	var fromScore float32
+	score, err := helper.ParseMCPArgs[float64]("from_score", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("from_score error: %s", err)
+	}
+	if score != nil {
+		fromScore = float32(*score)
+	}
+	offset, err := helper.ParseMCPArgs[float64]("offset", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("offset error: %s", err)
+	}
+	if offset != nil {
+		param.Offset = int32(*offset)
+	}
+	limit, err := helper.ParseMCPArgs[float64]("limit", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("limit error: %s", err)
+	}
+	if limit != nil {
+		param.Limit = int32(*limit)
+	}
+	return &listResourcesQuery{Request: param, FromScore: fromScore}, nil
+}
+
+// nextListResourcesCursor returns the cursor of the next page, or an empty string if this is the last page.
+func (s *Server) nextListResourcesCursor(query *listResourcesQuery, resources *finding.ListResourceResponse) (string, error) {
+	offset, lastID := query.Request.Offset, query.LastID
+	next := int64(offset) + int64(query.Request.Limit)
+	if query.Request.Limit <= 0 || next >= int64(resources.Total) || len(resources.ResourceId) == 0 {
+		return "", nil
+	}
+	query.Request.Offset = int32(next)
+	query.LastID = resources.ResourceId[len(resources.ResourceId)-1]
+	defer func() { query.Request.Offset, query.LastID = offset, lastID }()
+	return s.issueCursor("list_resources", query.Request.ProjectId, query)
+}
+
+// resumeResourceOffset moves the offset of the query decoded from the cursor to the resource after LastID,
+// so that the pages do not skip or repeat the resources added or removed after the cursor was issued.
+func resumeResourceOffset(ctx context.Context, riskenClient *risken.Client, query *listResourcesQuery) error {
+	param := query.Request
+	offset, limit := param.Offset, param.Limit
+	next, err := keysetOffset(offset, query.LastID, param.Direction == "desc", func(offset int32) (uint64, uint32, bool, error) {
+		param.Offset, param.Limit = offset, 1
+		resp, err := riskenClient.ListResource(ctx, param)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if len(resp.ResourceId) == 0 {
+			return 0, resp.Total, false, nil
+		}
+		return resp.ResourceId[0], resp.Total, true, nil
+	})
+	param.Offset, param.Limit = offset, limit
+	if err != nil {
+		return err
+	}
+	param.Offset = next
+	return nil
+}
+
+// fetchResources gets the resources and counts their active findings with the score or higher.
+// Resources without such findings are excluded if fromScore is specified.
+func (s *Server) fetchResources(ctx context.Context, riskenClient *risken.Client, projectID uint32, resourceIDs []uint64, fromScore float32) ([]*ResourceInfo, []*ResourceError) {
+	resources := make([]*ResourceInfo, len(resourceIDs))
+	errs := make([]error, len(resourceIDs))
+	s.runParallel(len(resourceIDs), func(i int) {
+		resources[i], errs[i] = getResourceInfo(ctx, riskenClient, projectID, resourceIDs[i], fromScore)
+	})
+
+	fetched := []*ResourceInfo{}
+	failed := []*ResourceError{}
+	for i, r := range resources {
+		if errs[i] != nil {
+			failed = append(failed, &ResourceError{ResourceID: resourceIDs[i], Error: errs[i].Error()})
+			continue
+		}
+		if fromScore > 0 && r.Findings == 0 {
+			continue
+		}
+		fetched = append(fetched, r)
+	}
+	return fetched, failed
+}
+
+func getResourceInfo(ctx context.Context, riskenClient *risken.Client, projectID uint32, resourceID uint64, fromScore float32) (*ResourceInfo, error) {
+	resource, err := getResource(ctx, riskenClient, projectID, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	info := &ResourceInfo{
+		ResourceID:   resource.ResourceId,
+		ResourceName: resource.ResourceName,
+		CreatedAt:    formatUnixTime(resource.CreatedAt),
+		UpdatedAt:    formatUnixTime(resource.UpdatedAt),
+	}
+	param := &finding.ListFindingRequest{
+		ProjectId:    projectID,
+		ResourceName: []string{resource.ResourceName},
+		FromScore:    fromScore,
+		Status:       finding.FindingStatus_FINDING_ACTIVE,
+		Limit:        1,
+	}
+	findings, err := riskenClient.ListFinding(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	info.Findings = findings.Total
+	if findings.Total == 0 {
+		return info, nil
+	}
+
+	// The prefix count is exact unless another resource shares the name prefix.
+	// The findings are not fetched to drop the others, because it costs a call for each finding of each resource in the list.
+	siblings, err := riskenClient.ListResource(ctx, &finding.ListResourceRequest{
+		ProjectId:    projectID,
+		ResourceName: []string{resource.ResourceName},
+		Limit:        1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	info.FindingsApproximate = siblings.Total > 1
+	return info, nil
+}
+
+func getResource(ctx context.Context, riskenClient *risken.Client, projectID uint32, resourceID uint64) (*finding.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	resp, err := riskenClient.GetResource(ctx, &finding.GetResourceRequest{
+		ProjectId:  projectID,
+		ResourceId: resourceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Resource == nil || resp.Resource.ResourceId == 0 {
+		return nil, errors.New("resource not found")
+	}
+	return resp.Resource, nil
+}
+
+func (s *Server) GetResource() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_resource",
+			mcp.WithDescription(fmt.Sprintf("Get RISKEN resource with its tags and findings (highest score first). "+
+				"Up to %d findings of the resources sharing the name prefix are scanned, and truncated is true if the scan stopped before the end. Use this when a request include \"resource\", \"asset\", \"リソース\", \"アセット\"...", resourceFindingScanLimit)),
+			mcp.WithNumber(
+				"resource_id",
+				mcp.Description("Resource ID."),
+				mcp.Required(),
+			),
+			mcp.WithNumber(
+				"status",
+				mcp.Description("Status of the findings. (0: all, 1: active, 2: pending)"),
+				mcp.DefaultNumber(1),
+				mcp.Enum("0", "1", "2"),
+			),
+			mcp.WithNumber(
+				"finding_limit",
+				mcp.Description("Limit of the findings."),
+				mcp.DefaultNumber(defaultResourceFindingLimit),
+				mcp.Max(maxResourceFindingLimit),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			params, err := s.ParseGetResourceParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			detail, err := s.getResourceDetail(ctx, riskenClient, params.ProjectId, params.ResourceId, params.Status, params.Limit)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get resource: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(detail)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal resource: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+// GetResourceParams is the params of get_resource.
+type GetResourceParams struct {
+	ProjectId  uint32
+	ResourceId uint64
+	Status     finding.FindingStatus
+	Limit      int32
+}
+
+func (s *Server) ParseGetResourceParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*GetResourceParams, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	param := &GetResourceParams{
+		ProjectId: p.ProjectId,
+		Status:    finding.FindingStatus_FINDING_ACTIVE,
+		Limit:     defaultResourceFindingLimit,
+	}
+
+	resourceID, err := helper.ParseMCPArgs[float64]("resource_id", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("resource_id error: %s", err)
+	}
+	if resourceID == nil {
+		return nil, errors.New("resource_id is required")
+	}
+	param.ResourceId = uint64(*resourceID)
+	status, err := helper.ParseMCPArgs[float64]("status", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("status error: %s", err)
+	}
+	if status != nil {
+		param.Status = finding.FindingStatus(int32(*status))
+	}
+	limit, err := helper.ParseMCPArgs[float64]("finding_limit", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("finding_limit error: %s", err)
+	}
+	if limit != nil {
+		param.Limit = int32(min(max(*limit, 1), maxResourceFindingLimit))
+	}
+	return param, nil
+}
+
+func (s *Server) getResourceDetail(ctx context.Context, riskenClient *risken.Client, projectID uint32, resourceID uint64, status finding.FindingStatus, limit int32) (*ResourceDetail, error) {
+	resource, err := getResource(ctx, riskenClient, projectID, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := riskenClient.ListResourceTag(ctx, &finding.ListResourceTagRequest{
+		ProjectId:  projectID,
+		ResourceId: resourceID,
+		Limit:      resourceTagListLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	findings, findingErrors, truncated, err := s.scanResourceFindings(ctx, riskenClient, &finding.ListFindingRequest{
+		ProjectId:    projectID,
+		ResourceName: []string{resource.ResourceName},
+		Status:       status,
+		Sort:         "score",
+		Direction:    "desc",
+	}, resource.ResourceName, int(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &ResourceDetail{
+		ResourceID:    resource.ResourceId,
+		ResourceName:  resource.ResourceName,
+		Tags:          []string{},
+		Findings:      findings,
+		FindingErrors: findingErrors,
+		Truncated:     truncated,
+		CreatedAt:     formatUnixTime(resource.CreatedAt),
+		UpdatedAt:     formatUnixTime(resource.UpdatedAt),
+	}
+	for _, t := range tags.Tag {
+		detail.Tags = append(detail.Tags, t.Tag)
+	}
+	return detail, nil
+}
+
+// scanResourceFindings pages through the findings matched by the resource_name prefix of param and returns
+// up to limit findings whose resource name is exactly resourceName, in the order of param.
+// It stops after resourceFindingScanLimit findings, and then truncated is true if there are more findings to scan.
+func (s *Server) scanResourceFindings(ctx context.Context, riskenClient *risken.Client, param *finding.ListFindingRequest, resourceName string, limit int) (findings []*finding.Finding, findingErrors []*FindingError, truncated bool, err error) {
+	findings = []*finding.Finding{}
+	findingErrors = []*FindingError{}
+	param.Limit = resourceFindingPageSize
+	for param.Offset = 0; param.Offset < resourceFindingScanLimit; param.Offset += resourceFindingPageSize {
+		resp, err := riskenClient.ListFinding(ctx, param)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		fetched, fetchErrors := s.fetchFindings(ctx, riskenClient, param.ProjectId, resp.FindingId)
+		findingErrors = append(findingErrors, fetchErrors...)
+		for _, f := range fetched {
+			if f.ResourceName != resourceName {
+				continue
+			}
+			findings = append(findings, f)
+			if len(findings) >= limit {
+				return findings, findingErrors, false, nil
+			}
+		}
+		if len(resp.FindingId) < resourceFindingPageSize || int(param.Offset)+len(resp.FindingId) >= int(resp.Total) {
+			return findings, findingErrors, false, nil
+		}
+	}
+	return findings, findingErrors, true, nil
+}
+
+func (s *Server) GetResourceResource() (mcp.ResourceTemplate, server.ResourceTemplateHandlerFunc) {
+	return mcp.NewResourceTemplate(
+			"resource://{project_id}/{resource_id}",
+			"RISKEN Resource",
+		),
+		s.ResourceResourceContentsHandler()
+}
+
+func (s *Server) ResourceResourceContentsHandler() func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		riskenClient, err := s.GetRISKENClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+		}
+
+		p, err := s.getResourceProject(ctx, riskenClient, request)
+		if err != nil {
+			return nil, err
+		}
+		resourceID, err := parseResourceID("resource_id", request)
+		if err != nil {
+			return nil, err
+		}
+
+		// Call RISKEN API
+		detail, err := s.getResourceDetail(ctx, riskenClient, p.ProjectId, resourceID, finding.FindingStatus_FINDING_ACTIVE, defaultResourceFindingLimit)
+		if err != nil {
+			s.invalidateOnAuthError(ctx, riskenClient, err)
+			return nil, errors.New("failed to get resource")
+		}
+		jsonData, err := json.Marshal(detail)
+		if err != nil {
+			return nil, errors.New("failed to marshal resource")
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,267 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newTestResourceHandler returns a fake RISKEN API handler for the resource APIs.
+func newTestResourceHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v1/finding/get-resource":
+			switch q.Get("resource_id") {
+			case "1":
+				_, _ = w.Write([]byte(`{"data":{"resource":{"resource_id":1,"resource_name":"res-a","created_at":1735689600}}}`))
+			case "2":
+				_, _ = w.Write([]byte(`{"data":{"resource":{"resource_id":2,"resource_name":"res-b"}}}`))
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		case "/api/v1/finding/list-resource":
+			// res-ab shares the prefix of res-a
+			if q.Get("resource_name") == "res-a" {
+				_, _ = w.Write([]byte(`{"data":{"resource_id":[1],"total":2}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"resource_id":[2],"total":1}}`))
+		case "/api/v1/finding/list-resource-tag":
+			_, _ = w.Write([]byte(`{"data":{"tag":[{"tag":"aws"},{"tag":"iam"}],"total":2}}`))
+		case "/api/v1/finding/list-finding":
+			if q.Get("resource_name") == "res-a" {
+				_, _ = w.Write([]byte(`{"data":{"finding_id":[10,11],"total":2}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"total":0}}`))
+		case "/api/v1/finding/get-finding":
+			// finding 11 belongs to another resource that shares the prefix
+			resourceName := "res-a"
+			if q.Get("finding_id") == "11" {
+				resourceName = "res-ab"
+			}
+			_, _ = w.Write([]byte(`{"data":{"finding":{"finding_id":` + q.Get("finding_id") + `,"resource_name":"` + resourceName + `"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestFetchResources(t *testing.T) {
+	tests := []struct {
+		name      string
+		fromScore float32
+		want      []*ResourceInfo
+	}{
+		{
+			name: "all resources",
+			want: []*ResourceInfo{
+				{ResourceID: 1, ResourceName: "res-a", Findings: 2, FindingsApproximate: true, CreatedAt: "2025-01-01T00:00:00Z"},
+				{ResourceID: 2, ResourceName: "res-b"},
+			},
+		},
+		{
+			name:      "score filter",
+			fromScore: 0.5,
+			want: []*ResourceInfo{
+				{ResourceID: 1, ResourceName: "res-a", Findings: 2, FindingsApproximate: true, CreatedAt: "2025-01-01T00:00:00Z"},
+			},
+		},
+	}
+
+	client := newTestRISKENClient(t, newTestResourceHandler())
+	s := newTestServer(client, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := s.fetchResources(context.Background(), client, 1, []uint64{1, 2, 3}, tt.fromScore)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("fetchResources() mismatch (-want +got):\n%s", diff)
+			}
+			if len(errs) != 1 || errs[0].ResourceID != 3 {
+				t.Errorf("fetchResources() errors = %v, want resource 3", errs)
+			}
+		})
+	}
+}
+
+func TestGetResourceDetail(t *testing.T) {
+	client := newTestRISKENClient(t, newTestResourceHandler())
+	s := newTestServer(client, nil)
+
+	got, err := s.getResourceDetail(context.Background(), client, 1, 1, finding.FindingStatus_FINDING_ACTIVE, 10)
+	if err != nil {
+		t.Fatalf("getResourceDetail() error = %v", err)
+	}
+	want := &ResourceDetail{
+		ResourceID:    1,
+		ResourceName:  "res-a",
+		Tags:          []string{"aws", "iam"},
+		Findings:      []*finding.Finding{{FindingId: 10, ResourceName: "res-a"}},
+		FindingErrors: []*FindingError{},
+		CreatedAt:     "2025-01-01T00:00:00Z",
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(finding.Finding{})); diff != "" {
+		t.Errorf("getResourceDetail() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := s.getResourceDetail(context.Background(), client, 1, 3, finding.FindingStatus_FINDING_ACTIVE, 10); err == nil {
+		t.Error("getResourceDetail() error = nil, want error")
+	}
+}
+
+func TestScanResourceFindings(t *testing.T) {
+	tests := []struct {
+		name          string
+		findings      int
+		ownIDs        map[uint64]bool
+		limit         int
+		wantIDs       []uint64
+		wantTruncated bool
+	}{
+		{
+			name:     "own findings after a page of siblings",
+			findings: 150,
+			ownIDs:   map[uint64]bool{120: true, 140: true},
+			limit:    10,
+			wantIDs:  []uint64{120, 140},
+		},
+		{
+			name:     "stop at the limit",
+			findings: 150,
+			ownIDs:   map[uint64]bool{1: true, 2: true, 3: true},
+			limit:    2,
+			wantIDs:  []uint64{1, 2},
+		},
+		{
+			name:          "scan limit",
+			findings:      resourceFindingScanLimit + 10,
+			ownIDs:        map[uint64]bool{10: true, uint64(resourceFindingScanLimit + 5): true},
+			limit:         10,
+			wantIDs:       []uint64{10},
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				q := r.URL.Query()
+				switch r.URL.Path {
+				case "/api/v1/finding/list-finding":
+					offset, _ := strconv.Atoi(q.Get("offset"))
+					limit, _ := strconv.Atoi(q.Get("limit"))
+					ids := []uint64{}
+					for id := offset + 1; id <= min(offset+limit, tt.findings); id++ {
+						ids = append(ids, uint64(id))
+					}
+					data, _ := json.Marshal(ids)
+					_, _ = fmt.Fprintf(w, `{"data":{"finding_id":%s,"count":%d,"total":%d}}`, data, len(ids), tt.findings)
+				case "/api/v1/finding/get-finding":
+					id, _ := strconv.ParseUint(q.Get("finding_id"), 10, 64)
+					resourceName := "bucket-logs"
+					if tt.ownIDs[id] {
+						resourceName = "bucket"
+					}
+					_, _ = fmt.Fprintf(w, `{"data":{"finding":{"finding_id":%d,"resource_name":"%s"}}}`, id, resourceName)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			s := newTestServer(client, nil)
+
+			param := &finding.ListFindingRequest{ProjectId: 1, ResourceName: []string{"bucket"}}
+			got, errs, truncated, err := s.scanResourceFindings(context.Background(), client, param, "bucket", tt.limit)
+			if err != nil {
+				t.Fatalf("scanResourceFindings() error = %v", err)
+			}
+			gotIDs := []uint64{}
+			for _, f := range got {
+				gotIDs = append(gotIDs, f.FindingId)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Errorf("scanResourceFindings() mismatch (-want +got):\n%s", diff)
+			}
+			if len(errs) != 0 {
+				t.Errorf("scanResourceFindings() errors = %v, want none", errs)
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("scanResourceFindings() truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestParseListResourcesParamsCursor(t *testing.T) {
+	tests := []struct {
+		name       string
+		ids        []uint64
+		wantOffset int32
+	}{
+		{
+			name:       "unchanged",
+			ids:        []uint64{1, 2, 3, 4, 5, 6},
+			wantOffset: 3,
+		},
+		{
+			name:       "removed before",
+			ids:        []uint64{1, 3, 4, 5, 6},
+			wantOffset: 2,
+		},
+		{
+			name:       "added before",
+			ids:        []uint64{0, 1, 2, 3, 4, 5, 6},
+			wantOffset: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signinCount int32
+			projectHandler := newTestProjectHandler(&signinCount)
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/finding/list-resource" {
+					projectHandler(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+				limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+				page := []uint64{}
+				for i := offset; i < len(tt.ids) && len(page) < limit; i++ {
+					page = append(page, tt.ids[i])
+				}
+				data, _ := json.Marshal(&finding.ListResourceResponse{ResourceId: page, Total: uint32(len(tt.ids))})
+				_, _ = fmt.Fprintf(w, `{"data":%s}`, data)
+			})
+			s := newTestServer(client, nil)
+			cursor, err := s.issueCursor("list_resources", 1, &listResourcesQuery{
+				Request: &finding.ListResourceRequest{ProjectId: 1, Sort: "resource_id", Direction: "asc", Offset: 3, Limit: 3},
+				LastID:  3,
+			})
+			if err != nil {
+				t.Fatalf("issueCursor() error = %v", err)
+			}
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"cursor": cursor}
+			got, err := s.ParseListResourcesParams(context.Background(), req, client)
+			if err != nil {
+				t.Fatalf("ParseListResourcesParams() error = %v", err)
+			}
+			if got.Request.Offset != tt.wantOffset || got.Request.Limit != 3 {
+				t.Errorf("ParseListResourcesParams() offset, limit = %d, %d, want %d, 3", got.Request.Offset, got.Request.Limit, tt.wantOffset)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/ca-risken/core/proto/finding"
 	"github.com/ca-risken/go-risken"
@@ -13,11 +14,62 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+const (
+	// timeRangeScanPageSize and maxTimeRangeScan limit the findings scanned in a call to apply from_at and to_at.
+	timeRangeScanPageSize = 100
+	maxTimeRangeScan      = 1000
+
+	// fetchAllPageSize is the page size of ListFinding in fetch_all mode.
+	fetchAllPageSize = 100
+)
+
+// findingSortParams maps the sort param of search_finding to the sort of RISKEN ListFinding.
+// RISKEN cannot sort by created_at, but finding IDs are assigned in creation order.
+var findingSortParams = map[string]string{
+	"score":      "score",
+	"updated_at": "updated_at",
+	"created_at": "finding_id",
+}
+
 type SearchFindingResponse struct {
 	Findings []*finding.Finding `json:"findings,omitempty"`
-	Total    uint32             `json:"total"`
-	Offset   int32              `json:"offset"`
-	Limit    int32              `json:"limit"`
+	Errors   []*FindingError    `json:"errors,omitempty"`
+	// Total is the total of the findings in RISKEN. from_at and to_at are not applied.
+	Total  uint32 `json:"total"`
+	Offset int32  `json:"offset"`
+	Limit  int32  `json:"limit"`
+	// Truncated is true if the scan of the time range or fetch_all stopped at the limit before the end.
+	Truncated  bool   `json:"truncated,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// searchFindingQuery is the query of search_finding encoded in the cursor.
+type searchFindingQuery struct {
+	Request   *finding.ListFindingRequest `json:"request"`
+	TimeRange *findingTimeRange           `json:"time_range,omitempty"`
+	// LastID is the finding ID before the offset in the created_at order, to resume after it even if the findings changed.
+	LastID     uint64 `json:"last_id,omitempty"`
+	FetchAll   bool   `json:"fetch_all,omitempty"`
+	DataFormat string `json:"data_format,omitempty"`
+}
+
+// findingTimeRange is the range of updated_at of the findings in unix time. Zero means unbounded.
+// RISKEN ListFinding has no time filter, so the range is applied to the fetched findings.
+type findingTimeRange struct {
+	From int64 `json:"from,omitempty"`
+	To   int64 `json:"to,omitempty"`
+}
+
+func (r *findingTimeRange) contains(unix int64) bool {
+	return (r.From == 0 || unix >= r.From) && (r.To == 0 || unix <= r.To)
+}
+
+// passed reports whether the findings after the given one are all out of the range in the updated_at order.
+func (r *findingTimeRange) passed(unix int64, param *finding.ListFindingRequest) bool {
+	if param.Direction == "asc" {
+		return r.To != 0 && unix > r.To
+	}
+	return r.From != 0 && unix < r.From
 }
 
 func (s *Server) SearchFinding() (tool mcp.Tool, handler server.ToolHandlerFunc) {
@@ -34,12 +86,16 @@ func (s *Server) SearchFinding() (tool mcp.Tool, handler server.ToolHandlerFunc)
 			mcp.WithArray(
 				"data_source",
 				mcp.Description("RISKEN DataSource. e.g. aws, google, code (like github, gitlab, etc.), osint, diagnosis, azure, ..."),
-				mcp.Enum("aws", "google", "code", "osint", "diagnosis", "azure"),
+				mcp.Enum(findingDataSources...),
 			),
 			mcp.WithArray(
 				"resource_name",
 				mcp.Description("RISKEN ResourceName. e.g. \"arn:aws:iam::123456789012:user/test-user\" ..."),
 			),
+			mcp.WithArray(
+				"tag",
+				mcp.Description("Tag of the findings. e.g. \"ticket:SEC-123\", \"owner:payments\" ..."),
+			),
 			mcp.WithNumber(
 				"from_score",
 				mcp.Description("Minimum score of the findings."),
@@ -47,12 +103,40 @@ func (s *Server) SearchFinding() (tool mcp.Tool, handler server.ToolHandlerFunc)
 				mcp.Max(1.0),
 				mcp.Min(0.0),
 			),
+			mcp.WithNumber(
+				"to_score",
+				mcp.Description("Maximum score of the findings."),
+				mcp.Max(1.0),
+				mcp.Min(0.0),
+			),
 			mcp.WithNumber(
 				"status",
 				mcp.Description("Status of the findings. (0: all, 1: active, 2: pending)"),
 				mcp.DefaultNumber(1),
 				mcp.Enum("0", "1", "2"),
 			),
+			mcp.WithString(
+				"from_at",
+				mcp.Description(fmt.Sprintf("Search the findings updated at or after this time. ISO8601 format. e.g. 2025-01-01, 2025-01-01T00:00:00Z. "+
+					"RISKEN has no time filter, so the findings are scanned in updated_at order, up to %d findings per call. "+
+					"offset and total count all the findings, and next_cursor continues the scan.", maxTimeRangeScan)),
+			),
+			mcp.WithString(
+				"to_at",
+				mcp.Description("Search the findings updated at or before this time. ISO8601 format. A date without time means the end of the day."),
+			),
+			mcp.WithString(
+				"sort",
+				mcp.Description("Sort key of the findings. from_at and to_at require updated_at. (default: updated_at if from_at or to_at is specified) "+
+					"next_cursor resumes after the last finding in the default order (created_at asc) and with created_at. With the other orders, the pages can skip or repeat findings updated between the pages."),
+				mcp.Enum("score", "updated_at", "created_at"),
+			),
+			mcp.WithString(
+				"direction",
+				mcp.Description("Sort direction of the findings."),
+				mcp.Enum("asc", "desc"),
+				mcp.DefaultString("desc"),
+			),
 			mcp.WithNumber(
 				"offset",
 				mcp.Description("Offset of the findings."),
@@ -65,6 +149,20 @@ func (s *Server) SearchFinding() (tool mcp.Tool, handler server.ToolHandlerFunc)
 				mcp.Max(100),
 				mcp.Min(1),
 			),
+			mcp.WithBoolean(
+				"fetch_all",
+				mcp.Description(fmt.Sprintf("Follow the pages and return all the matched findings at once, up to %d findings or %d KB (limit is ignored). "+
+					"With from_at or to_at, it also stops after scanning %d findings. If the result is truncated, continue with next_cursor. "+
+					"Use this for questions like \"all findings on this resource\".", s.config.FetchAllMaxFindings, s.config.FetchAllMaxBytes/1024, maxTimeRangeScan)),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithString(
+				"data_format",
+				mcp.Description("Format of the data field of the findings. raw: JSON of the data source as is, markdown: readable summary of what was found, where and why."),
+				mcp.Enum(findingDataFormatRaw, findingDataFormatMarkdown),
+				mcp.DefaultString(findingDataFormatRaw),
+			),
+			withCursor(),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			riskenClient, err := s.GetRISKENClient(ctx)
@@ -73,33 +171,31 @@ func (s *Server) SearchFinding() (tool mcp.Tool, handler server.ToolHandlerFunc)
 			}
 
 			// Parse params
-			params, err := s.ParseSearchFindingParams(ctx, req, riskenClient)
+			query, err := s.ParseSearchFindingParams(ctx, req, riskenClient)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
 			}
 
 			// Call RISKEN API
-			findings, err := riskenClient.ListFinding(ctx, params)
-			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get findings: %s", err)), nil
-			}
-
-			searchResult := &SearchFindingResponse{
-				Findings: []*finding.Finding{},
-				Total:    uint32(findings.Total),
-				Offset:   int32(params.Offset),
-				Limit:    int32(params.Limit),
-			}
-			for _, fid := range findings.FindingId {
-				finding, err := riskenClient.GetFinding(ctx, &finding.GetFindingRequest{
-					ProjectId: params.ProjectId,
-					FindingId: fid,
-				})
+			if query.FetchAll {
+				searchResult, err := s.searchAllFindings(ctx, riskenClient, query)
 				if err != nil {
-					return mcp.NewToolResultError(fmt.Sprintf("failed to get finding: %s", err)), nil
+					s.invalidateOnAuthError(ctx, riskenClient, err)
+					return mcp.NewToolResultError(fmt.Sprintf("failed to get findings: %s", err)), nil
 				}
-				searchResult.Findings = append(searchResult.Findings, finding.Finding)
+				formatFindingData(searchResult.Findings, query.DataFormat)
+				jsonData, err := json.Marshal(searchResult)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("failed to marshal search result: %s", err)), nil
+				}
+				return mcp.NewToolResultText(string(jsonData)), nil
+			}
+			searchResult, err := s.searchFindings(ctx, riskenClient, query)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get findings: %s", err)), nil
 			}
+			formatFindingData(searchResult.Findings, query.DataFormat)
 			jsonData, err := json.Marshal(searchResult)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal search result: %s", err)), nil
@@ -108,14 +204,46 @@ func (s *Server) SearchFinding() (tool mcp.Tool, handler server.ToolHandlerFunc)
 		}
 }
 
-func (s *Server) ParseSearchFindingParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*finding.ListFindingRequest, error) {
+// ParseSearchFindingParams returns the ListFinding request and the time range of the findings (nil if not specified).
+// If the cursor is specified, the query of the next page is decoded from the cursor instead.
+func (s *Server) ParseSearchFindingParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*searchFindingQuery, error) {
 	p, err := s.GetCurrentProject(ctx, riskenClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project: %s", err)
 	}
+	query := &searchFindingQuery{}
+	ok, err := s.parseCursor(req, "search_finding", p.ProjectId, query)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if err := resumeFindingOffset(ctx, riskenClient, query); err != nil {
+			return nil, fmt.Errorf("failed to resume from cursor: %s", err)
+		}
+		return query, nil
+	}
+	fetchAll, err := helper.ParseMCPArgs[bool]("fetch_all", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("fetch_all error: %s", err)
+	}
+	if fetchAll != nil {
+		query.FetchAll = *fetchAll
+	}
+	dataFormat, err := helper.ParseMCPArgs[string]("data_format", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("data_format error: %s", err)
+	}
+	if dataFormat != nil {
+		if *dataFormat != findingDataFormatRaw && *dataFormat != findingDataFormatMarkdown {
+			return nil, fmt.Errorf("data_format must be %s or %s: %s", findingDataFormatRaw, findingDataFormatMarkdown, *dataFormat)
+		}
+		query.DataFormat = *dataFormat
+	}
 	param := &finding.ListFindingRequest{
 		ProjectId: p.ProjectId,
 		// Default params
+		Sort:      "finding_id",
+		Direction: "asc",
 		Offset:    0,
 		Limit:     10,
 		FromScore: 0.1,
@@ -135,7 +263,8 @@ func (s *Server) ParseSearchFindingParams(ctx context.Context, req mcp.CallToolR
 		param.FindingId = uint64(*findingID)
 		param.FromScore = 0.0
 		param.Status = finding.FindingStatus_FINDING_UNKNOWN
-		return param, nil // finding_id is specified, so return immediately
+		query.Request = param
+		return query, nil // finding_id is specified, so return immediately
 	}
 
 	alertID, err := helper.ParseMCPArgs[float64]("alert_id", req.GetArguments())
@@ -145,12 +274,124 @@ func (s *Server) ParseSearchFindingParams(ctx context.Context, req mcp.CallToolR
 	if alertID != nil {
 		param.AlertId = uint32(*alertID)
 		param.FromScore = 0.0
-		return param, nil // alert_id is specified, so return immediately
+		query.Request = param
+		return query, nil // alert_id is specified, so return immediately
+	}
+
+	if err := parseFindingFilterArgs(req, param); err != nil {
+		return nil, err
 	}
+	status, err := helper.ParseMCPArgs[float64]("status", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("status error: %s", err)
+	}
+	if status != nil {
+		param.Status = finding.FindingStatus(int32(*status))
+	}
+	offset, err := helper.ParseMCPArgs[float64]("offset", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("offset error: %s", err)
+	}
+	if offset != nil {
+		param.Offset = int32(*offset)
+	}
+	limit, err := helper.ParseMCPArgs[float64]("limit", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("limit error: %s", err)
+	}
+	if limit != nil {
+		param.Limit = int32(*limit)
+	}
+	timeRange, err := parseFindingTimeRange(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := parseFindingSortArgs(req, param, timeRange != nil); err != nil {
+		return nil, err
+	}
+	query.Request = param
+	query.TimeRange = timeRange
+	return query, nil
+}
 
+// parseFindingTimeRange parses from_at and to_at. It returns nil if both are not specified.
+func parseFindingTimeRange(req mcp.CallToolRequest) (*findingTimeRange, error) {
+	fromAt, err := helper.ParseMCPArgs[string]("from_at", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("from_at error: %s", err)
+	}
+	toAt, err := helper.ParseMCPArgs[string]("to_at", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("to_at error: %s", err)
+	}
+	if fromAt == nil && toAt == nil {
+		return nil, nil
+	}
+	timeRange := &findingTimeRange{}
+	if fromAt != nil {
+		t, err := helper.ParseISO8601Time(*fromAt)
+		if err != nil {
+			return nil, fmt.Errorf("from_at error: %s", err)
+		}
+		timeRange.From = t.Unix()
+	}
+	if toAt != nil {
+		t, err := helper.ParseISO8601Time(*toAt)
+		if err != nil {
+			return nil, fmt.Errorf("to_at error: %s", err)
+		}
+		if len(*toAt) == len(time.DateOnly) {
+			t = t.Add(24*time.Hour - time.Second)
+		}
+		timeRange.To = t.Unix()
+	}
+	if timeRange.To != 0 && timeRange.From > timeRange.To {
+		return nil, fmt.Errorf("from_at(%s) must be before to_at(%s)", *fromAt, *toAt)
+	}
+	return timeRange, nil
+}
+
+func parseFindingSortArgs(req mcp.CallToolRequest, param *finding.ListFindingRequest, hasTimeRange bool) error {
+	sort, err := helper.ParseMCPArgs[string]("sort", req.GetArguments())
+	if err != nil {
+		return fmt.Errorf("sort error: %s", err)
+	}
+	direction, err := helper.ParseMCPArgs[string]("direction", req.GetArguments())
+	if err != nil {
+		return fmt.Errorf("direction error: %s", err)
+	}
+	if sort == nil && direction == nil && !hasTimeRange {
+		// RISKEN default order. It is set explicitly so that the cursor can resume after the last finding ID.
+		param.Sort, param.Direction = "finding_id", "asc"
+		return nil
+	}
+	param.Sort = "updated_at"
+	if sort != nil {
+		v, ok := findingSortParams[*sort]
+		if !ok {
+			return fmt.Errorf("sort must be one of score, updated_at, created_at: %s", *sort)
+		}
+		if hasTimeRange && v != "updated_at" {
+			// The time range scan stops at the end of the range only in the updated_at order.
+			return fmt.Errorf("sort must be updated_at when from_at or to_at is specified: %s", *sort)
+		}
+		param.Sort = v
+	}
+	param.Direction = "desc"
+	if direction != nil {
+		if *direction != "asc" && *direction != "desc" {
+			return fmt.Errorf("direction must be asc or desc: %s", *direction)
+		}
+		param.Direction = *direction
+	}
+	return nil
+}
+
+// parseFindingFilterArgs parses the finding filters shared by search_finding and bulk_archive_findings.
+func parseFindingFilterArgs(req mcp.CallToolRequest, param *finding.ListFindingRequest) error {
 	dataSource, err := helper.ParseMCPArgs[[]any]("data_source", req.GetArguments())
 	if err != nil {
-		return nil, fmt.Errorf("data_source error: %s", err)
+		return fmt.Errorf("data_source error: %s", err)
 	}
 	if dataSource != nil {
 		for _, v := range *dataSource {
@@ -159,40 +400,351 @@ func (s *Server) ParseSearchFindingParams(ctx context.Context, req mcp.CallToolR
 	}
 	resourceName, err := helper.ParseMCPArgs[[]any]("resource_name", req.GetArguments())
 	if err != nil {
-		return nil, fmt.Errorf("resource_name error: %s", err)
+		return fmt.Errorf("resource_name error: %s", err)
 	}
 	if resourceName != nil {
 		for _, v := range *resourceName {
 			param.ResourceName = append(param.ResourceName, fmt.Sprintf("%v", v))
 		}
 	}
+	tag, err := helper.ParseMCPArgs[[]any]("tag", req.GetArguments())
+	if err != nil {
+		return fmt.Errorf("tag error: %s", err)
+	}
+	if tag != nil {
+		for _, v := range *tag {
+			param.Tag = append(param.Tag, fmt.Sprintf("%v", v))
+		}
+	}
 	fromScore, err := helper.ParseMCPArgs[float64]("from_score", req.GetArguments())
 	if err != nil {
-		return nil, fmt.Errorf("from_score error: %s", err)
+		return fmt.Errorf("from_score error: %s", err)
 	}
 	if fromScore != nil {
 		param.FromScore = float32(*fromScore)
 	}
-	status, err := helper.ParseMCPArgs[float64]("status", req.GetArguments())
+	toScore, err := helper.ParseMCPArgs[float64]("to_score", req.GetArguments())
 	if err != nil {
-		return nil, fmt.Errorf("status error: %s", err)
+		return fmt.Errorf("to_score error: %s", err)
 	}
-	if status != nil {
-		param.Status = finding.FindingStatus(int32(*status))
+	if toScore != nil {
+		param.ToScore = float32(*toScore)
 	}
-	offset, err := helper.ParseMCPArgs[float64]("offset", req.GetArguments())
+	if param.ToScore > 0 && param.FromScore > param.ToScore {
+		return fmt.Errorf("from_score(%v) must be less than or equal to to_score(%v)", param.FromScore, param.ToScore)
+	}
+	return nil
+}
+
+// searchFindings returns a page of the findings, with the cursor of the next page if there are more findings.
+func (s *Server) searchFindings(ctx context.Context, riskenClient *risken.Client, query *searchFindingQuery) (*SearchFindingResponse, error) {
+	param := query.Request
+	var result *SearchFindingResponse
+	next := int64(-1) // offset of the next page
+	var lastID uint64
+	if query.TimeRange != nil {
+		page, scanNext, err := s.searchFindingsInTimeRange(ctx, riskenClient, param, query.TimeRange)
+		if err != nil {
+			return nil, err
+		}
+		result, next = page, scanNext
+	} else {
+		findings, err := riskenClient.ListFinding(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		fetched, fetchErrors := s.fetchFindings(ctx, riskenClient, param.ProjectId, findings.FindingId)
+		result = &SearchFindingResponse{
+			Findings: fetched,
+			Errors:   fetchErrors,
+			Total:    uint32(findings.Total),
+			Offset:   int32(param.Offset),
+			Limit:    int32(param.Limit),
+		}
+		if end := int64(param.Offset) + int64(param.Limit); end < int64(findings.Total) {
+			next = end
+		}
+		if len(findings.FindingId) > 0 {
+			lastID = findings.FindingId[len(findings.FindingId)-1]
+		}
+	}
+	if next < 0 || param.Limit <= 0 {
+		return result, nil
+	}
+	cursor, err := s.nextSearchFindingCursor(query, next, lastID)
 	if err != nil {
-		return nil, fmt.Errorf("offset error: %s", err)
+		return nil, fmt.Errorf("failed to issue cursor: %w", err)
 	}
-	if offset != nil {
-		param.Offset = int32(*offset)
+	result.NextCursor = cursor
+	return result, nil
+}
+
+// nextSearchFindingCursor returns the cursor of the page from the next offset.
+// lastID is the finding before the next offset, and is used to resume in the created_at order.
+func (s *Server) nextSearchFindingCursor(query *searchFindingQuery, next int64, lastID uint64) (string, error) {
+	offset, prevID := query.Request.Offset, query.LastID
+	query.Request.Offset = int32(next)
+	query.LastID = 0
+	if query.Request.Sort == "finding_id" {
+		query.LastID = lastID
 	}
-	limit, err := helper.ParseMCPArgs[float64]("limit", req.GetArguments())
+	defer func() { query.Request.Offset, query.LastID = offset, prevID }()
+	return s.issueCursor("search_finding", query.Request.ProjectId, query)
+}
+
+// resumeFindingOffset moves the offset of the query decoded from the cursor to the finding after LastID,
+// so that the pages do not skip or repeat the findings added or removed after the cursor was issued.
+func resumeFindingOffset(ctx context.Context, riskenClient *risken.Client, query *searchFindingQuery) error {
+	param := query.Request
+	if query.LastID == 0 || param.Sort != "finding_id" {
+		return nil
+	}
+	offset, limit := param.Offset, param.Limit
+	next, err := keysetOffset(offset, query.LastID, param.Direction != "asc", func(offset int32) (uint64, uint32, bool, error) {
+		param.Offset, param.Limit = offset, 1
+		resp, err := riskenClient.ListFinding(ctx, param)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if len(resp.FindingId) == 0 {
+			return 0, resp.Total, false, nil
+		}
+		return resp.FindingId[0], resp.Total, true, nil
+	})
+	param.Offset, param.Limit = offset, limit
 	if err != nil {
-		return nil, fmt.Errorf("limit error: %s", err)
+		return err
 	}
-	if limit != nil {
-		param.Limit = int32(*limit)
+	param.Offset = next
+	return nil
+}
+
+// formatFindingData replaces the data of the findings with the Markdown summary if the format is markdown.
+func formatFindingData(findings []*finding.Finding, format string) {
+	if format != findingDataFormatMarkdown {
+		return
+	}
+	for _, f := range findings {
+		f.Data = renderFindingData(f)
+	}
+}
+
+// searchAllFindings follows the pages from the offset of the query until all the findings are returned,
+// or the number or the JSON size of the findings reaches the limit of fetch_all.
+// If it stops early, the result is truncated and has the cursor to continue from the next finding.
+func (s *Server) searchAllFindings(ctx context.Context, riskenClient *risken.Client, query *searchFindingQuery) (*SearchFindingResponse, error) {
+	param := query.Request
+	offset, limit := param.Offset, param.Limit
+	defer func() { param.Offset, param.Limit = offset, limit }()
+
+	result := &SearchFindingResponse{
+		Findings: []*finding.Finding{},
+		Errors:   []*FindingError{},
+		Offset:   offset,
+		Limit:    int32(s.config.FetchAllMaxFindings),
+	}
+	budget := &fetchAllBudget{maxFindings: s.config.FetchAllMaxFindings, maxBytes: s.config.FetchAllMaxBytes}
+	next := int64(-1) // offset of the first finding not returned
+	var lastID uint64 // finding ID before next
+	if query.TimeRange != nil {
+		scan, err := s.scanFindingsInTimeRange(ctx, riskenClient, param, query.TimeRange, func(f *finding.Finding) (bool, error) {
+			ok, err := budget.add(f)
+			if ok {
+				result.Findings = append(result.Findings, f)
+			}
+			return ok, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Total = scan.total
+		result.Errors = scan.errors
+		next = scan.next
+	} else {
+		param.Limit = fetchAllPageSize
+		for next < 0 {
+			resp, err := riskenClient.ListFinding(ctx, param)
+			if err != nil {
+				return nil, err
+			}
+			result.Total = resp.Total
+			fetched, fetchErrors := s.fetchFindings(ctx, riskenClient, param.ProjectId, resp.FindingId)
+			findings := make(map[uint64]*finding.Finding, len(fetched))
+			for _, f := range fetched {
+				findings[f.FindingId] = f
+			}
+			failed := make(map[uint64]*FindingError, len(fetchErrors))
+			for _, e := range fetchErrors {
+				failed[e.FindingID] = e
+			}
+			for i, id := range resp.FindingId {
+				if e, ok := failed[id]; ok {
+					result.Errors = append(result.Errors, e)
+					lastID = id
+					continue
+				}
+				f, ok := findings[id]
+				if !ok {
+					lastID = id
+					continue
+				}
+				ok, err := budget.add(f)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					next = int64(param.Offset) + int64(i)
+					break
+				}
+				result.Findings = append(result.Findings, f)
+				lastID = id
+			}
+			if next >= 0 || len(resp.FindingId) == 0 {
+				break
+			}
+			param.Offset += int32(len(resp.FindingId))
+			if int64(param.Offset) >= int64(resp.Total) {
+				break
+			}
+			if budget.full() {
+				next = int64(param.Offset)
+			}
+		}
+	}
+	if next < 0 {
+		return result, nil
+	}
+
+	result.Truncated = true
+	param.Offset, param.Limit = offset, limit
+	cursor, err := s.nextSearchFindingCursor(query, next, lastID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue cursor: %w", err)
+	}
+	result.NextCursor = cursor
+	return result, nil
+}
+
+// fetchAllBudget limits the number and the JSON size of the findings returned in fetch_all mode.
+type fetchAllBudget struct {
+	maxFindings int
+	maxBytes    int
+	findings    int
+	bytes       int
+}
+
+// add reports whether the finding fits in the budget, and counts it if so.
+// The first finding is always accepted so that the pagination can make progress.
+func (b *fetchAllBudget) add(f *finding.Finding) (bool, error) {
+	if b.full() {
+		return false, nil
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal finding: %w", err)
+	}
+	if b.findings > 0 && b.bytes+len(data) > b.maxBytes {
+		return false, nil
+	}
+	b.findings++
+	b.bytes += len(data)
+	return true, nil
+}
+
+func (b *fetchAllBudget) full() bool {
+	return b.findings >= b.maxFindings
+}
+
+// searchFindingsInTimeRange returns up to the limit of the findings in the time range, scanned from the offset.
+// It also returns the offset to continue the scan from, or -1 if there are no more findings in the range.
+func (s *Server) searchFindingsInTimeRange(ctx context.Context, riskenClient *risken.Client, param *finding.ListFindingRequest, timeRange *findingTimeRange) (*SearchFindingResponse, int64, error) {
+	result := &SearchFindingResponse{
+		Findings: []*finding.Finding{},
+		Offset:   param.Offset,
+		Limit:    param.Limit,
+	}
+	limit := int(param.Limit)
+	scan, err := s.scanFindingsInTimeRange(ctx, riskenClient, param, timeRange, func(f *finding.Finding) (bool, error) {
+		if len(result.Findings) >= limit {
+			return false, nil
+		}
+		result.Findings = append(result.Findings, f)
+		return true, nil
+	})
+	if err != nil {
+		return nil, -1, err
+	}
+	result.Errors = scan.errors
+	result.Total = scan.total
+	result.Truncated = scan.capped
+	return result, scan.next, nil
+}
+
+// timeRangeScan is the result of scanFindingsInTimeRange.
+type timeRangeScan struct {
+	errors []*FindingError
+	total  uint32 // total of ListFinding
+	next   int64  // offset to continue the scan from, or -1 if there are no more findings in the range
+	capped bool   // the scan stopped after maxTimeRangeScan findings
+}
+
+// scanFindingsInTimeRange scans the findings from the offset in the updated_at order, and passes the ones in the time range to add.
+// RISKEN ListFinding has no time filter, so every scanned finding is fetched to check updated_at.
+// The scan stops when add returns false, at the end of the range, or after maxTimeRangeScan findings.
+func (s *Server) scanFindingsInTimeRange(ctx context.Context, riskenClient *risken.Client, param *finding.ListFindingRequest,
+	timeRange *findingTimeRange, add func(*finding.Finding) (bool, error)) (*timeRangeScan, error) {
+	offset, limit := param.Offset, param.Limit
+	defer func() { param.Offset, param.Limit = offset, limit }()
+
+	scan := &timeRangeScan{errors: []*FindingError{}, next: -1}
+	param.Limit = timeRangeScanPageSize
+	for {
+		resp, err := riskenClient.ListFinding(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		scan.total = resp.Total
+		fetched, fetchErrors := s.fetchFindings(ctx, riskenClient, param.ProjectId, resp.FindingId)
+		findings := make(map[uint64]*finding.Finding, len(fetched))
+		for _, f := range fetched {
+			findings[f.FindingId] = f
+		}
+		failed := make(map[uint64]*FindingError, len(fetchErrors))
+		for _, e := range fetchErrors {
+			failed[e.FindingID] = e
+		}
+		for i, id := range resp.FindingId {
+			if e, ok := failed[id]; ok {
+				scan.errors = append(scan.errors, e)
+				continue
+			}
+			f, ok := findings[id]
+			if !ok {
+				continue
+			}
+			if timeRange.passed(f.UpdatedAt, param) {
+				return scan, nil
+			}
+			if !timeRange.contains(f.UpdatedAt) {
+				continue
+			}
+			ok, err := add(f)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				scan.next = int64(param.Offset) + int64(i)
+				return scan, nil
+			}
+		}
+		param.Offset += int32(len(resp.FindingId))
+		if len(resp.FindingId) < timeRangeScanPageSize || int64(param.Offset) >= int64(resp.Total) {
+			return scan, nil
+		}
+		if param.Offset-offset >= maxTimeRangeScan {
+			scan.next = int64(param.Offset)
+			scan.capped = true
+			return scan, nil
+		}
 	}
-	return param, nil
 }
@@ -0,0 +1,499 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseFindingTimeRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    *findingTimeRange
+		wantErr bool
+	}{
+		{
+			name: "not specified",
+			args: map[string]any{},
+			want: nil,
+		},
+		{
+			name: "date range",
+			args: map[string]any{"from_at": "2025-01-01", "to_at": "2025-01-01"},
+			want: &findingTimeRange{From: 1735689600, To: 1735775999},
+		},
+		{
+			name: "date-time",
+			args: map[string]any{"from_at": "2025-01-01T09:00:00+09:00"},
+			want: &findingTimeRange{From: 1735689600},
+		},
+		{
+			name:    "invalid format",
+			args:    map[string]any{"from_at": "yesterday"},
+			wantErr: true,
+		},
+		{
+			name:    "from after to",
+			args:    map[string]any{"from_at": "2025-01-02", "to_at": "2025-01-01"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			got, err := parseFindingTimeRange(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFindingTimeRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseFindingTimeRange() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseFindingSortArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          map[string]any
+		hasTimeRange  bool
+		wantSort      string
+		wantDirection string
+		wantErr       bool
+	}{
+		{
+			name:          "default order",
+			args:          map[string]any{},
+			wantSort:      "finding_id",
+			wantDirection: "asc",
+		},
+		{
+			name:          "default order with time range",
+			args:          map[string]any{},
+			hasTimeRange:  true,
+			wantSort:      "updated_at",
+			wantDirection: "desc",
+		},
+		{
+			name:          "created_at",
+			args:          map[string]any{"sort": "created_at", "direction": "asc"},
+			wantSort:      "finding_id",
+			wantDirection: "asc",
+		},
+		{
+			name:          "score",
+			args:          map[string]any{"sort": "score"},
+			wantSort:      "score",
+			wantDirection: "desc",
+		},
+		{
+			name:          "updated_at with time range",
+			args:          map[string]any{"sort": "updated_at", "direction": "asc"},
+			hasTimeRange:  true,
+			wantSort:      "updated_at",
+			wantDirection: "asc",
+		},
+		{
+			name:         "other sort with time range",
+			args:         map[string]any{"sort": "score"},
+			hasTimeRange: true,
+			wantErr:      true,
+		},
+		{
+			name:    "invalid sort",
+			args:    map[string]any{"sort": "description"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid direction",
+			args:    map[string]any{"direction": "up"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			param := &finding.ListFindingRequest{}
+			err := parseFindingSortArgs(req, param, tt.hasTimeRange)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFindingSortArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if param.Sort != tt.wantSort || param.Direction != tt.wantDirection {
+				t.Errorf("parseFindingSortArgs() = %s %s, want %s %s", param.Sort, param.Direction, tt.wantSort, tt.wantDirection)
+			}
+		})
+	}
+}
+
+func TestSearchFindingsInTimeRange(t *testing.T) {
+	// Finding N is updated at N*100, listed in updated_at desc order.
+	const totalFindings = 1200
+	tests := []struct {
+		name          string
+		timeRange     *findingTimeRange
+		offset, limit int32
+		wantIDs       []uint64
+		wantTruncated bool
+		wantNext      int32 // offset in next_cursor, 0 if no next page
+		wantListCalls int
+	}{
+		{
+			name:          "stop at the limit",
+			timeRange:     &findingTimeRange{From: 119000, To: 119800},
+			limit:         3,
+			wantIDs:       []uint64{1198, 1197, 1196},
+			wantNext:      5,
+			wantListCalls: 1,
+		},
+		{
+			name:          "stop at the end of the range",
+			timeRange:     &findingTimeRange{From: 119000, To: 119800},
+			offset:        5,
+			limit:         10,
+			wantIDs:       []uint64{1195, 1194, 1193, 1192, 1191, 1190},
+			wantListCalls: 1,
+		},
+		{
+			name:          "scan limit",
+			timeRange:     &findingTimeRange{To: 200},
+			limit:         10,
+			wantIDs:       []uint64{},
+			wantTruncated: true,
+			wantNext:      maxTimeRangeScan,
+			wantListCalls: maxTimeRangeScan / timeRangeScanPageSize,
+		},
+		{
+			name:          "continue the scan",
+			timeRange:     &findingTimeRange{To: 200},
+			offset:        maxTimeRangeScan,
+			limit:         10,
+			wantIDs:       []uint64{2, 1},
+			wantListCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listCalls := 0
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				q := r.URL.Query()
+				switch r.URL.Path {
+				case "/api/v1/finding/list-finding":
+					listCalls++
+					offset, _ := strconv.Atoi(q.Get("offset"))
+					limit, _ := strconv.Atoi(q.Get("limit"))
+					ids := []string{}
+					for id := totalFindings - offset; id > 0 && len(ids) < limit; id-- {
+						ids = append(ids, strconv.Itoa(id))
+					}
+					_, _ = fmt.Fprintf(w, `{"data":{"finding_id":[%s],"count":%d,"total":%d}}`, strings.Join(ids, ","), len(ids), totalFindings)
+				case "/api/v1/finding/get-finding":
+					id, _ := strconv.Atoi(q.Get("finding_id"))
+					_, _ = fmt.Fprintf(w, `{"data":{"finding":{"finding_id":%d,"updated_at":%d}}}`, id, id*100)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			s := newTestServer(client, nil)
+
+			query := &searchFindingQuery{
+				Request:   &finding.ListFindingRequest{ProjectId: 1, Sort: "updated_at", Direction: "desc", Offset: tt.offset, Limit: tt.limit},
+				TimeRange: tt.timeRange,
+			}
+			got, err := s.searchFindings(context.Background(), client, query)
+			if err != nil {
+				t.Fatalf("searchFindings() error = %v", err)
+			}
+			gotIDs := []uint64{}
+			for _, f := range got.Findings {
+				gotIDs = append(gotIDs, f.FindingId)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Errorf("searchFindings() findings mismatch (-want +got):\n%s", diff)
+			}
+			if got.Total != totalFindings || got.Offset != tt.offset || got.Limit != tt.limit || got.Truncated != tt.wantTruncated {
+				t.Errorf("searchFindings() total, offset, limit, truncated = %d, %d, %d, %v, want %d, %d, %d, %v",
+					got.Total, got.Offset, got.Limit, got.Truncated, totalFindings, tt.offset, tt.limit, tt.wantTruncated)
+			}
+			if listCalls != tt.wantListCalls {
+				t.Errorf("ListFinding calls = %d, want %d", listCalls, tt.wantListCalls)
+			}
+			if diff := cmp.Diff(&finding.ListFindingRequest{ProjectId: 1, Sort: "updated_at", Direction: "desc", Offset: tt.offset, Limit: tt.limit}, query.Request, cmpopts.IgnoreUnexported(finding.ListFindingRequest{})); diff != "" {
+				t.Errorf("query was modified (-want +got):\n%s", diff)
+			}
+			if tt.wantNext == 0 {
+				if got.NextCursor != "" {
+					t.Errorf("searchFindings() next_cursor = %s, want empty", got.NextCursor)
+				}
+				return
+			}
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"cursor": got.NextCursor}
+			next := &searchFindingQuery{}
+			if _, err := s.parseCursor(req, "search_finding", 1, next); err != nil {
+				t.Fatalf("parseCursor() error = %v", err)
+			}
+			if next.Request.Offset != tt.wantNext || next.Request.Limit != tt.limit || next.TimeRange == nil {
+				t.Errorf("next query = offset:%d limit:%d time_range:%v, want offset:%d limit:%d", next.Request.Offset, next.Request.Limit, next.TimeRange, tt.wantNext, tt.limit)
+			}
+		})
+	}
+}
+
+func TestSearchAllFindings(t *testing.T) {
+	// Findings are listed in descending order of ID.
+	const defaultTotalFindings = 250
+	tests := []struct {
+		name          string
+		totalFindings int
+		config        *Config
+		offset        int32
+		timeRange     *findingTimeRange
+		failIDs       map[uint64]bool
+		wantCount     int
+		wantFirst     uint64
+		wantErrors    int
+		wantNext      int32
+		wantTruncated bool
+	}{
+		{
+			name:      "all",
+			config:    &Config{FetchAllMaxFindings: 500},
+			offset:    10,
+			wantCount: 240,
+			wantFirst: 240,
+		},
+		{
+			name:          "max findings",
+			config:        &Config{FetchAllMaxFindings: 150},
+			wantCount:     150,
+			wantFirst:     250,
+			wantNext:      150,
+			wantTruncated: true,
+		},
+		{
+			name:          "max findings at the end of the page",
+			config:        &Config{FetchAllMaxFindings: 100},
+			wantCount:     100,
+			wantFirst:     250,
+			wantNext:      100,
+			wantTruncated: true,
+		},
+		{
+			name:          "max bytes",
+			config:        &Config{FetchAllMaxBytes: 120}, // about 37 bytes per finding
+			offset:        5,
+			failIDs:       map[uint64]bool{244: true},
+			wantCount:     3,
+			wantFirst:     245,
+			wantErrors:    1,
+			wantNext:      9,
+			wantTruncated: true,
+		},
+		{
+			name:          "time range",
+			config:        &Config{FetchAllMaxFindings: 5},
+			timeRange:     &findingTimeRange{From: 24000},
+			wantCount:     5,
+			wantFirst:     250,
+			wantNext:      5,
+			wantTruncated: true,
+		},
+		{
+			name:          "time range scan limit",
+			totalFindings: 1200,
+			config:        &Config{FetchAllMaxFindings: 2000},
+			timeRange:     &findingTimeRange{To: 119900},
+			wantCount:     maxTimeRangeScan - 1,
+			wantFirst:     1199,
+			wantNext:      maxTimeRangeScan,
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totalFindings := tt.totalFindings
+			if totalFindings == 0 {
+				totalFindings = defaultTotalFindings
+			}
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				q := r.URL.Query()
+				switch r.URL.Path {
+				case "/api/v1/finding/list-finding":
+					offset, _ := strconv.Atoi(q.Get("offset"))
+					limit, _ := strconv.Atoi(q.Get("limit"))
+					ids := []string{}
+					for id := totalFindings - offset; id > 0 && len(ids) < limit; id-- {
+						ids = append(ids, strconv.Itoa(id))
+					}
+					_, _ = fmt.Fprintf(w, `{"data":{"finding_id":[%s],"count":%d,"total":%d}}`, strings.Join(ids, ","), len(ids), totalFindings)
+				case "/api/v1/finding/get-finding":
+					id, _ := strconv.Atoi(q.Get("finding_id"))
+					if tt.failIDs[uint64(id)] {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					_, _ = fmt.Fprintf(w, `{"data":{"finding":{"finding_id":%d,"updated_at":%d}}}`, id, id*100)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			s := newTestServer(client, tt.config)
+
+			query := &searchFindingQuery{
+				Request:   &finding.ListFindingRequest{ProjectId: 1, Offset: tt.offset, Limit: 10},
+				TimeRange: tt.timeRange,
+				FetchAll:  true,
+			}
+			got, err := s.searchAllFindings(context.Background(), client, query)
+			if err != nil {
+				t.Fatalf("searchAllFindings() error = %v", err)
+			}
+			if len(got.Findings) != tt.wantCount || len(got.Errors) != tt.wantErrors || got.Truncated != tt.wantTruncated {
+				t.Fatalf("searchAllFindings() = findings:%d errors:%d truncated:%v, want findings:%d errors:%d truncated:%v",
+					len(got.Findings), len(got.Errors), got.Truncated, tt.wantCount, tt.wantErrors, tt.wantTruncated)
+			}
+			if got.Findings[0].FindingId != tt.wantFirst {
+				t.Errorf("searchAllFindings() first finding = %d, want %d", got.Findings[0].FindingId, tt.wantFirst)
+			}
+			if diff := cmp.Diff(&finding.ListFindingRequest{ProjectId: 1, Offset: tt.offset, Limit: 10}, query.Request, cmpopts.IgnoreUnexported(finding.ListFindingRequest{})); diff != "" {
+				t.Errorf("query was modified (-want +got):\n%s", diff)
+			}
+			if !tt.wantTruncated {
+				if got.NextCursor != "" {
+					t.Errorf("searchAllFindings() next_cursor = %s, want empty", got.NextCursor)
+				}
+				return
+			}
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"cursor": got.NextCursor}
+			next := &searchFindingQuery{}
+			if _, err := s.parseCursor(req, "search_finding", 1, next); err != nil {
+				t.Fatalf("parseCursor() error = %v", err)
+			}
+			if next.Request.Offset != tt.wantNext || next.Request.Limit != 10 || !next.FetchAll {
+				t.Errorf("next query = offset:%d limit:%d fetch_all:%v, want offset:%d limit:10 fetch_all:true", next.Request.Offset, next.Request.Limit, next.FetchAll, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestSearchFindingCursorResume(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       map[string]any
+		ids        []uint64 // findings in the listed order
+		changed    []uint64 // findings after the first page is returned
+		wantFirst  []uint64
+		wantNext   []uint64
+		wantOffset int32
+	}{
+		{
+			name: "default order",
+			args: map[string]any{"limit": float64(3), "from_score": float64(0)},
+			ids:  []uint64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+			// Findings before the cursor are removed, and one after the cursor is added.
+			changed:    []uint64{12, 13, 14, 15, 16, 17, 18, 19, 20, 21},
+			wantFirst:  []uint64{11, 12, 13},
+			wantNext:   []uint64{14, 15, 16},
+			wantOffset: 2,
+		},
+		{
+			name: "created_at desc",
+			args: map[string]any{"sort": "created_at", "limit": float64(3), "from_score": float64(0)},
+			ids:  []uint64{20, 19, 18, 17, 16, 15, 14, 13, 12, 11},
+			// Findings are added before the cursor, and one after the cursor is removed.
+			changed:    []uint64{22, 21, 20, 19, 18, 17, 15, 14, 13, 12, 11},
+			wantFirst:  []uint64{20, 19, 18},
+			wantNext:   []uint64{17, 15, 14},
+			wantOffset: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := tt.ids
+			var signinCount int32
+			projectHandler := newTestProjectHandler(&signinCount)
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				q := r.URL.Query()
+				switch r.URL.Path {
+				case "/api/v1/finding/list-finding":
+					offset, _ := strconv.Atoi(q.Get("offset"))
+					limit, _ := strconv.Atoi(q.Get("limit"))
+					page := []string{}
+					for i := offset; i < len(ids) && len(page) < limit; i++ {
+						page = append(page, strconv.FormatUint(ids[i], 10))
+					}
+					_, _ = fmt.Fprintf(w, `{"data":{"finding_id":[%s],"count":%d,"total":%d}}`, strings.Join(page, ","), len(page), len(ids))
+				case "/api/v1/finding/get-finding":
+					_, _ = fmt.Fprintf(w, `{"data":{"finding":{"finding_id":%s}}}`, q.Get("finding_id"))
+				default:
+					projectHandler(w, r)
+				}
+			})
+			s := newTestServer(client, nil)
+			_, handler := s.SearchFinding()
+
+			search := func(args map[string]any) *SearchFindingResponse {
+				t.Helper()
+				req := mcp.CallToolRequest{}
+				req.Params.Arguments = args
+				result, err := handler(context.Background(), req)
+				if err != nil {
+					t.Fatalf("handler() error = %v", err)
+				}
+				text := result.Content[0].(mcp.TextContent).Text
+				if result.IsError {
+					t.Fatalf("handler() returned error: %s", text)
+				}
+				got := &SearchFindingResponse{}
+				if err := json.Unmarshal([]byte(text), got); err != nil {
+					t.Fatalf("failed to unmarshal result: %v", err)
+				}
+				return got
+			}
+			findingIDs := func(resp *SearchFindingResponse) []uint64 {
+				got := []uint64{}
+				for _, f := range resp.Findings {
+					got = append(got, f.FindingId)
+				}
+				return got
+			}
+
+			first := search(tt.args)
+			if diff := cmp.Diff(tt.wantFirst, findingIDs(first)); diff != "" {
+				t.Fatalf("first page mismatch (-want +got):\n%s", diff)
+			}
+			ids = tt.changed
+			next := search(map[string]any{"cursor": first.NextCursor})
+			if diff := cmp.Diff(tt.wantNext, findingIDs(next)); diff != "" {
+				t.Errorf("next page mismatch (-want +got):\n%s", diff)
+			}
+			if next.Offset != tt.wantOffset || next.NextCursor == "" {
+				t.Errorf("next page offset = %d, next_cursor = %q, want offset %d and next_cursor", next.Offset, next.NextCursor, tt.wantOffset)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// findingDataSources is the data source prefixes of RISKEN findings.
+var findingDataSources = []string{"aws", "google", "code", "osint", "diagnosis", "azure"}
+
+// Score bands defined in README. The lower bound is inclusive.
+const (
+	scoreBandMediumFrom   = 0.3
+	scoreBandHighFrom     = 0.6
+	scoreBandCriticalFrom = 0.8
+)
+
+const (
+	defaultTopResources = 10
+	maxTopResources     = 50
+
+	// topResourceSampleSize is the number of the highest scored findings used to rank the top resources.
+	topResourceSampleSize = 100
+)
+
+type FindingSummary struct {
+	Total        uint32                     `json:"total"`
+	ByScore      *ScoreBandCount            `json:"by_score"`
+	ByDataSource map[string]*ScoreBandCount `json:"by_data_source"`
+	ByStatus     map[string]uint32          `json:"by_status"`
+	TopResources []*ResourceSummary         `json:"top_resources"`
+	// Errors is the findings that failed to get. TopResources is ranked without them.
+	Errors []*FindingError `json:"errors,omitempty"`
+}
+
+// ScoreBandCount is the number of findings in each score band.
+type ScoreBandCount struct {
+	Total    uint32 `json:"total"`
+	Low      uint32 `json:"low"`
+	Medium   uint32 `json:"medium"`
+	High     uint32 `json:"high"`
+	Critical uint32 `json:"critical"`
+}
+
+// ResourceSummary is the number of findings and the max score of a resource.
+type ResourceSummary struct {
+	ResourceName string  `json:"resource_name"`
+	Findings     int     `json:"findings"`
+	MaxScore     float32 `json:"max_score"`
+}
+
+func (s *Server) SummarizeFindings() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("summarize_findings",
+			mcp.WithDescription("Summarize the active RISKEN findings in the project by data source, score band (low: 0.0~0.3, medium: 0.3~0.6, high: 0.6~0.8, critical: 0.8~1.0), status and top resources. "+
+				"Use this when a request include \"summary\", \"posture\", \"overview\", \"サマリ\", \"概要\", \"状況\"..."),
+			mcp.WithNumber(
+				"top_resources",
+				mcp.Description(fmt.Sprintf("Number of the top resources. The top resources are ranked from the %d highest scored findings.", topResourceSampleSize)),
+				mcp.DefaultNumber(defaultTopResources),
+				mcp.Max(maxTopResources),
+				mcp.Min(0),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+			p, err := s.GetCurrentProject(ctx, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %s", err)), nil
+			}
+			topResources, err := helper.ParseMCPArgs[float64]("top_resources", req.GetArguments())
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: top_resources error: %s", err)), nil
+			}
+			top := defaultTopResources
+			if topResources != nil {
+				top = min(max(int(*topResources), 0), maxTopResources)
+			}
+
+			summary, err := s.summarizeFindings(ctx, riskenClient, p.ProjectId, top)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to summarize findings: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(summary)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal summary: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) summarizeFindings(ctx context.Context, riskenClient *risken.Client, projectID uint32, topResources int) (*FindingSummary, error) {
+	// Count the findings above each band's lower bound, then subtract to get the count of each band.
+	// The first group is the whole project, and the rest are per data source.
+	bandFroms := []float32{0, scoreBandMediumFrom, scoreBandHighFrom, scoreBandCriticalFrom}
+	dataSources := append([]string{""}, findingDataSources...)
+	queries := []*finding.ListFindingRequest{}
+	for _, ds := range dataSources {
+		for _, from := range bandFroms {
+			q := &finding.ListFindingRequest{
+				ProjectId: projectID,
+				FromScore: from,
+				Status:    finding.FindingStatus_FINDING_ACTIVE,
+				Limit:     1,
+			}
+			if ds != "" {
+				q.DataSource = []string{ds}
+			}
+			queries = append(queries, q)
+		}
+	}
+	pendingQuery := &finding.ListFindingRequest{
+		ProjectId: projectID,
+		Status:    finding.FindingStatus_FINDING_PENDING,
+		Limit:     1,
+	}
+	queries = append(queries, pendingQuery)
+
+	totals := make([]uint32, len(queries))
+	errs := make([]error, len(queries))
+	s.runParallel(len(queries), func(i int) {
+		totals[i], errs[i] = countFindings(ctx, riskenClient, queries[i])
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	summary := &FindingSummary{
+		ByDataSource: map[string]*ScoreBandCount{},
+	}
+	for i, ds := range dataSources {
+		counts := newScoreBandCount(totals[i*len(bandFroms) : (i+1)*len(bandFroms)])
+		if ds == "" {
+			summary.Total = counts.Total
+			summary.ByScore = counts
+			continue
+		}
+		if counts.Total > 0 {
+			summary.ByDataSource[ds] = counts
+		}
+	}
+	summary.ByStatus = map[string]uint32{
+		"active":  summary.Total,
+		"pending": totals[len(totals)-1],
+	}
+
+	summary.TopResources = []*ResourceSummary{}
+	if topResources > 0 && summary.Total > 0 {
+		resp, err := riskenClient.ListFinding(ctx, &finding.ListFindingRequest{
+			ProjectId: projectID,
+			Status:    finding.FindingStatus_FINDING_ACTIVE,
+			Sort:      "score",
+			Direction: "desc",
+			Limit:     topResourceSampleSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		findings, fetchErrors := s.fetchFindings(ctx, riskenClient, projectID, resp.FindingId)
+		summary.TopResources = rankResources(findings, topResources)
+		if len(fetchErrors) > 0 {
+			summary.Errors = fetchErrors
+		}
+	}
+	return summary, nil
+}
+
+func countFindings(ctx context.Context, riskenClient *risken.Client, param *finding.ListFindingRequest) (uint32, error) {
+	resp, err := riskenClient.ListFinding(ctx, param)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Total, nil
+}
+
+// newScoreBandCount converts the cumulative counts (score >= 0, 0.3, 0.6, 0.8) into the count of each band.
+func newScoreBandCount(cumulative []uint32) *ScoreBandCount {
+	sub := func(a, b uint32) uint32 {
+		// The counts may be inconsistent when findings are updated during the aggregation.
+		if a < b {
+			return 0
+		}
+		return a - b
+	}
+	return &ScoreBandCount{
+		Total:    cumulative[0],
+		Low:      sub(cumulative[0], cumulative[1]),
+		Medium:   sub(cumulative[1], cumulative[2]),
+		High:     sub(cumulative[2], cumulative[3]),
+		Critical: cumulative[3],
+	}
+}
+
+// rankResources groups the findings by resource, and returns the top resources ordered by max score and number of findings.
+func rankResources(findings []*finding.Finding, limit int) []*ResourceSummary {
+	resources := map[string]*ResourceSummary{}
+	for _, f := range findings {
+		r, ok := resources[f.ResourceName]
+		if !ok {
+			r = &ResourceSummary{ResourceName: f.ResourceName}
+			resources[f.ResourceName] = r
+		}
+		r.Findings++
+		r.MaxScore = max(r.MaxScore, f.Score)
+	}
+
+	ranked := make([]*ResourceSummary, 0, len(resources))
+	for _, r := range resources {
+		ranked = append(ranked, r)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].MaxScore != ranked[j].MaxScore {
+			return ranked[i].MaxScore > ranked[j].MaxScore
+		}
+		if ranked[i].Findings != ranked[j].Findings {
+			return ranked[i].Findings > ranked[j].Findings
+		}
+		return ranked[i].ResourceName < ranked[j].ResourceName
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
@@ -0,0 +1,118 @@
+package riskenmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewScoreBandCount(t *testing.T) {
+	tests := []struct {
+		name       string
+		cumulative []uint32
+		want       *ScoreBandCount
+	}{
+		{
+			name:       "bands",
+			cumulative: []uint32{10, 6, 3, 1},
+			want:       &ScoreBandCount{Total: 10, Low: 4, Medium: 3, High: 2, Critical: 1},
+		},
+		{
+			name:       "inconsistent counts",
+			cumulative: []uint32{5, 6, 3, 3},
+			want:       &ScoreBandCount{Total: 5, Low: 0, Medium: 3, High: 0, Critical: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newScoreBandCount(tt.cumulative)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("newScoreBandCount() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRankResources(t *testing.T) {
+	findings := []*finding.Finding{
+		{ResourceName: "a", Score: 0.5},
+		{ResourceName: "b", Score: 0.9},
+		{ResourceName: "c", Score: 0.5},
+		{ResourceName: "c", Score: 0.3},
+		{ResourceName: "d", Score: 0.1},
+	}
+	tests := []struct {
+		name  string
+		limit int
+		want  []*ResourceSummary
+	}{
+		{
+			name:  "ordered by max score and findings",
+			limit: 10,
+			want: []*ResourceSummary{
+				{ResourceName: "b", Findings: 1, MaxScore: 0.9},
+				{ResourceName: "c", Findings: 2, MaxScore: 0.5},
+				{ResourceName: "a", Findings: 1, MaxScore: 0.5},
+				{ResourceName: "d", Findings: 1, MaxScore: 0.1},
+			},
+		},
+		{
+			name:  "limit",
+			limit: 1,
+			want: []*ResourceSummary{
+				{ResourceName: "b", Findings: 1, MaxScore: 0.9},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rankResources(findings, tt.limit)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("rankResources() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSummarizeFindingsFetchErrors(t *testing.T) {
+	var signinCount int32
+	projectHandler := newTestProjectHandler(&signinCount)
+	client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/finding/list-finding":
+			_, _ = w.Write([]byte(`{"data":{"finding_id":[1,2,3],"count":3,"total":3}}`))
+		case "/api/v1/finding/get-finding":
+			id := r.URL.Query().Get("finding_id")
+			if id == "2" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"data":{"finding":{"finding_id":%s,"project_id":1,"resource_name":"resource-%s","score":0.5}}}`, id, id)
+		default:
+			projectHandler(w, r)
+		}
+	})
+	s := newTestServer(client, nil)
+
+	got, err := s.summarizeFindings(context.Background(), client, 1, defaultTopResources)
+	if err != nil {
+		t.Fatalf("summarizeFindings() error = %v", err)
+	}
+	gotResources := []string{}
+	for _, r := range got.TopResources {
+		gotResources = append(gotResources, r.ResourceName)
+	}
+	if diff := cmp.Diff([]string{"resource-1", "resource-3"}, gotResources); diff != "" {
+		t.Errorf("summarizeFindings() top resources mismatch (-want +got):\n%s", diff)
+	}
+	if len(got.Errors) != 1 || got.Errors[0].FindingID != 2 {
+		t.Errorf("summarizeFindings() errors = %v, want the error of finding_id=2", got.Errors)
+	}
+}
@@ -0,0 +1,238 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const findingTagListLimit = 200
+
+func (s *Server) ListFindingTags() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_finding_tags",
+			mcp.WithDescription("List tags of RISKEN finding. If finding_id is not specified, list all tag names used in the project. Use this when a request include \"tag\", \"タグ\"..."),
+			mcp.WithNumber(
+				"finding_id",
+				mcp.Description("Finding ID."),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			params, err := s.ParseListFindingTagParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			var resp any
+			if params.FindingId == 0 {
+				resp, err = riskenClient.ListFindingTagName(ctx, &finding.ListFindingTagNameRequest{
+					ProjectId: params.ProjectId,
+					Limit:     params.Limit,
+				})
+			} else {
+				resp, err = riskenClient.ListFindingTag(ctx, params)
+			}
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list finding tags: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(resp)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) ParseListFindingTagParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*finding.ListFindingTagRequest, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	param := &finding.ListFindingTagRequest{
+		ProjectId: p.ProjectId,
+		Limit:     findingTagListLimit,
+	}
+
+	findingID, err := helper.ParseMCPArgs[float64]("finding_id", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("finding_id error: %s", err)
+	}
+	if findingID != nil {
+		param.FindingId = uint64(*findingID)
+	}
+	return param, nil
+}
+
+func (s *Server) TagFinding() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("tag_finding",
+			mcp.WithDescription("Add a tag to RISKEN finding. e.g. \"ticket:SEC-123\", \"owner:payments\". Use this when a request include \"tag\", \"タグ付け\"..."),
+			mcp.WithNumber(
+				"finding_id",
+				mcp.Description("Finding ID."),
+				mcp.Required(),
+			),
+			mcp.WithString(
+				"tag",
+				mcp.Description("Tag. e.g. ticket:SEC-123"),
+				mcp.Required(),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			params, err := s.ParseTagFindingParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			resp, err := riskenClient.TagFinding(ctx, params)
+			if err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to tag finding: %s", err)), nil
+			}
+			jsonData, err := json.Marshal(resp)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %s", err)), nil
+			}
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
+
+func (s *Server) ParseTagFindingParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*finding.TagFindingRequest, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	findingID, tag, err := parseFindingTagArgs(req)
+	if err != nil {
+		return nil, err
+	}
+	return &finding.TagFindingRequest{
+		ProjectId: p.ProjectId,
+		Tag: &finding.FindingTagForUpsert{
+			ProjectId: p.ProjectId,
+			FindingId: findingID,
+			Tag:       tag,
+		},
+	}, nil
+}
+
+func (s *Server) UntagFinding() (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("untag_finding",
+			mcp.WithDescription("Remove a tag from RISKEN finding. Use this when a request include \"untag\", \"remove tag\", \"タグを外す\"..."),
+			mcp.WithNumber(
+				"finding_id",
+				mcp.Description("Finding ID."),
+				mcp.Required(),
+			),
+			mcp.WithString(
+				"tag",
+				mcp.Description("Tag to remove. e.g. ticket:SEC-123"),
+				mcp.Required(),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			riskenClient, err := s.GetRISKENClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+			}
+
+			// Parse params
+			params, err := s.ParseUntagFindingParams(ctx, req, riskenClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+			}
+
+			// Call RISKEN API
+			if err := riskenClient.UntagFinding(ctx, params); err != nil {
+				s.invalidateOnAuthError(ctx, riskenClient, err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to untag finding: %s", err)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Successfully removed the tag(finding_tag_id=%d)", params.FindingTagId)), nil
+		}
+}
+
+func (s *Server) ParseUntagFindingParams(ctx context.Context, req mcp.CallToolRequest, riskenClient *risken.Client) (*finding.UntagFindingRequest, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %s", err)
+	}
+	findingID, tag, err := parseFindingTagArgs(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the finding_tag_id from the tag name
+	t, err := findFindingTag(ctx, riskenClient, p.ProjectId, findingID, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list finding tags: %s", err)
+	}
+	if t == nil {
+		return nil, fmt.Errorf("tag not found: finding_id=%d, tag=%s", findingID, tag)
+	}
+	return &finding.UntagFindingRequest{
+		ProjectId:    p.ProjectId,
+		FindingTagId: t.FindingTagId,
+	}, nil
+}
+
+// findFindingTag walks all pages of the finding tags and returns the tag with the name, or nil if the finding does not have it.
+func findFindingTag(ctx context.Context, riskenClient *risken.Client, projectID uint32, findingID uint64, tag string) (*finding.FindingTag, error) {
+	param := &finding.ListFindingTagRequest{
+		ProjectId: projectID,
+		FindingId: findingID,
+		Limit:     findingTagListLimit,
+	}
+	for param.Offset = 0; ; param.Offset += findingTagListLimit {
+		resp, err := riskenClient.ListFindingTag(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range resp.Tag {
+			if t.Tag == tag {
+				return t, nil
+			}
+		}
+		if len(resp.Tag) < findingTagListLimit || int(param.Offset)+len(resp.Tag) >= int(resp.Total) {
+			return nil, nil
+		}
+	}
+}
+
+func parseFindingTagArgs(req mcp.CallToolRequest) (uint64, string, error) {
+	findingID, err := helper.ParseMCPArgs[float64]("finding_id", req.GetArguments())
+	if err != nil {
+		return 0, "", fmt.Errorf("finding_id error: %s", err)
+	}
+	if findingID == nil {
+		return 0, "", errors.New("finding_id is required")
+	}
+	tag, err := helper.ParseMCPArgs[string]("tag", req.GetArguments())
+	if err != nil {
+		return 0, "", fmt.Errorf("tag error: %s", err)
+	}
+	if tag == nil || strings.TrimSpace(*tag) == "" {
+		return 0, "", errors.New("tag is required")
+	}
+	return uint64(*findingID), strings.TrimSpace(*tag), nil
+}
@@ -0,0 +1,213 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/google/go-cmp/cmp"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestTagFinding(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantReq string
+		wantErr string
+	}{
+		{
+			name:    "success",
+			args:    map[string]any{"finding_id": float64(10), "tag": " ticket:SEC-123 "},
+			wantReq: `{"project_id":1,"tag":{"finding_id":10,"project_id":1,"tag":"ticket:SEC-123"}}`,
+		},
+		{
+			name:    "no finding_id",
+			args:    map[string]any{"tag": "ticket:SEC-123"},
+			wantErr: "finding_id is required",
+		},
+		{
+			name:    "blank tag",
+			args:    map[string]any{"finding_id": float64(10), "tag": " "},
+			wantErr: "tag is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signinCount int32
+			projectHandler := newTestProjectHandler(&signinCount)
+			gotReq := ""
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/finding/tag-finding" {
+					projectHandler(w, r)
+					return
+				}
+				body, _ := io.ReadAll(r.Body)
+				gotReq = string(body)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data":{"tag":{"finding_tag_id":100,"finding_id":10,"project_id":1,"tag":"ticket:SEC-123"}}}`))
+			})
+			s := newTestServer(client, nil)
+			_, handler := s.TagFinding()
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handler() error = %v", err)
+			}
+			text := result.Content[0].(mcp.TextContent).Text
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Errorf("handler() = %q, want error %q", text, tt.wantErr)
+				}
+				if gotReq != "" {
+					t.Errorf("TagFinding called with %s, want no call", gotReq)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("handler() returned error: %s", text)
+			}
+			if diff := cmp.Diff(tt.wantReq, gotReq); diff != "" {
+				t.Errorf("TagFinding request mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUntagFinding(t *testing.T) {
+	tests := []struct {
+		name          string
+		tagCount      int
+		tag           string
+		wantTagID     uint64
+		wantListCalls int
+		wantErr       string
+	}{
+		{
+			name:          "first page",
+			tagCount:      3,
+			tag:           "tag-2",
+			wantTagID:     102,
+			wantListCalls: 1,
+		},
+		{
+			name:          "later page",
+			tagCount:      findingTagListLimit*2 + 10,
+			tag:           fmt.Sprintf("tag-%d", findingTagListLimit*2+5),
+			wantTagID:     uint64(100 + findingTagListLimit*2 + 5),
+			wantListCalls: 3,
+		},
+		{
+			name:          "not tagged",
+			tagCount:      findingTagListLimit + 1,
+			tag:           "unknown",
+			wantListCalls: 2,
+			wantErr:       "tag not found: finding_id=10, tag=unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signinCount int32
+			projectHandler := newTestProjectHandler(&signinCount)
+			listCalls := 0
+			var gotUntag *finding.UntagFindingRequest
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/v1/finding/list-finding-tag":
+					listCalls++
+					offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+					limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+					tags := []*finding.FindingTag{}
+					for i := offset; i < tt.tagCount && i < offset+limit; i++ {
+						tags = append(tags, &finding.FindingTag{
+							FindingTagId: uint64(100 + i),
+							FindingId:    10,
+							ProjectId:    1,
+							Tag:          fmt.Sprintf("tag-%d", i),
+						})
+					}
+					data, _ := json.Marshal(&finding.ListFindingTagResponse{Tag: tags, Count: uint32(len(tags)), Total: uint32(tt.tagCount)})
+					_, _ = fmt.Fprintf(w, `{"data":%s}`, data)
+				case "/api/v1/finding/untag-finding":
+					gotUntag = &finding.UntagFindingRequest{}
+					_ = json.NewDecoder(r.Body).Decode(gotUntag)
+					_, _ = w.Write([]byte(`{}`))
+				default:
+					projectHandler(w, r)
+				}
+			})
+			s := newTestServer(client, nil)
+			_, handler := s.UntagFinding()
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"finding_id": float64(10), "tag": tt.tag}
+			result, err := handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handler() error = %v", err)
+			}
+			text := result.Content[0].(mcp.TextContent).Text
+			if listCalls != tt.wantListCalls {
+				t.Errorf("ListFindingTag calls = %d, want %d", listCalls, tt.wantListCalls)
+			}
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Errorf("handler() = %q, want error %q", text, tt.wantErr)
+				}
+				if gotUntag != nil {
+					t.Errorf("UntagFinding called with %v, want no call", gotUntag)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("handler() returned error: %s", text)
+			}
+			if gotUntag == nil || gotUntag.FindingTagId != tt.wantTagID || gotUntag.ProjectId != 1 {
+				t.Errorf("UntagFinding request = %v, want finding_tag_id=%d", gotUntag, tt.wantTagID)
+			}
+		})
+	}
+}
+
+func TestParseFindingFilterArgsTag(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+		want []string
+	}{
+		{
+			name: "tags",
+			args: map[string]any{"tag": []any{"ticket:SEC-123", "owner:payments"}},
+			want: []string{"ticket:SEC-123", "owner:payments"},
+		},
+		{
+			name: "not specified",
+			args: map[string]any{},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			param := &finding.ListFindingRequest{}
+			if err := parseFindingFilterArgs(req, param); err != nil {
+				t.Fatalf("parseFindingFilterArgs() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, param.Tag); diff != "" {
+				t.Errorf("parseFindingFilterArgs() tag mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
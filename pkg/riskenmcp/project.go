@@ -3,10 +3,13 @@ package riskenmcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/ca-risken/core/proto/alert"
 	"github.com/ca-risken/core/proto/project"
 	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -29,6 +32,114 @@ func (s *Server) GetProject() (tool mcp.Tool, handler server.ToolHandlerFunc) {
 		}
 }
 
+// ProjectSummary is the security posture of the project: the active findings and the active alerts.
+type ProjectSummary struct {
+	Project      *project.Project `json:"project"`
+	Findings     *FindingSummary  `json:"findings"`
+	ActiveAlerts map[string]int   `json:"active_alerts"`
+}
+
+func (s *Server) GetProjectResource() (mcp.ResourceTemplate, server.ResourceTemplateHandlerFunc) {
+	return mcp.NewResourceTemplate(
+			"project://{project_id}",
+			"RISKEN Project",
+		),
+		s.ProjectResourceContentsHandler()
+}
+
+func (s *Server) ProjectResourceContentsHandler() func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		riskenClient, err := s.GetRISKENClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+		}
+
+		p, err := s.getResourceProject(ctx, riskenClient, request)
+		if err != nil {
+			return nil, err
+		}
+		jsonData, err := json.Marshal(p)
+		if err != nil {
+			return nil, errors.New("failed to marshal project")
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		}, nil
+	}
+}
+
+func (s *Server) GetProjectSummaryResource() (mcp.ResourceTemplate, server.ResourceTemplateHandlerFunc) {
+	return mcp.NewResourceTemplate(
+			"risken://{project_id}/summary",
+			"RISKEN Project Summary",
+			mcp.WithTemplateDescription("The active findings by score band, data source and top resources, and the number of the active alerts by severity."),
+		),
+		s.ProjectSummaryResourceContentsHandler()
+}
+
+func (s *Server) ProjectSummaryResourceContentsHandler() func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		riskenClient, err := s.GetRISKENClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+		}
+
+		p, err := s.getResourceProject(ctx, riskenClient, request)
+		if err != nil {
+			return nil, err
+		}
+
+		// Call RISKEN API
+		summary, err := s.summarizeProject(ctx, riskenClient, p)
+		if err != nil {
+			s.invalidateOnAuthError(ctx, riskenClient, err)
+			return nil, errors.New("failed to summarize project")
+		}
+		jsonData, err := json.Marshal(summary)
+		if err != nil {
+			return nil, errors.New("failed to marshal summary")
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		}, nil
+	}
+}
+
+func (s *Server) summarizeProject(ctx context.Context, riskenClient *risken.Client, p *project.Project) (*ProjectSummary, error) {
+	findings, err := s.summarizeFindings(ctx, riskenClient, p.ProjectId, defaultTopResources)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := riskenClient.ListAlert(ctx, &alert.ListAlertRequest{
+		ProjectId: p.ProjectId,
+		Status:    []alert.Status{alert.Status_ACTIVE},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	summary := &ProjectSummary{
+		Project:      p,
+		Findings:     findings,
+		ActiveAlerts: map[string]int{"total": len(alerts.Alert)},
+	}
+	for _, a := range alerts.Alert {
+		summary.ActiveAlerts[a.Severity]++
+	}
+	return summary, nil
+}
+
+// GetCurrentProject returns the project of the RISKEN client.
+// The result is cached per client for Config.ProjectCacheTTL to avoid calling Signin and ListProject on every request.
 func (s *Server) GetCurrentProject(ctx context.Context, riskenClient *risken.Client) (*project.Project, error) {
 	if riskenClient == nil {
 		client, err := s.GetRISKENClient(ctx)
@@ -38,6 +149,11 @@ func (s *Server) GetCurrentProject(ctx context.Context, riskenClient *risken.Cli
 		riskenClient = client
 	}
 
+	cacheKey := getRISKENClientKey(ctx, riskenClient)
+	if p, ok := s.projectCache.Get(cacheKey); ok {
+		return p, nil
+	}
+
 	resp, err := riskenClient.Signin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to signin: %w", err)
@@ -49,6 +165,18 @@ func (s *Server) GetCurrentProject(ctx context.Context, riskenClient *risken.Cli
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
+	if len(project.Project) == 0 {
+		return nil, fmt.Errorf("project not found: project_id=%d", resp.ProjectID)
+	}
 
+	s.projectCache.Set(cacheKey, project.Project[0])
 	return project.Project[0], nil
 }
+
+// invalidateOnAuthError drops the cached project of the client when the RISKEN API rejects its credentials.
+func (s *Server) invalidateOnAuthError(ctx context.Context, riskenClient *risken.Client, err error) {
+	if !helper.IsAuthError(err) {
+		return
+	}
+	s.projectCache.Delete(getRISKENClientKey(ctx, riskenClient))
+}
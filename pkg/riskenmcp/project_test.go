@@ -0,0 +1,119 @@
+package riskenmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ca-risken/core/proto/project"
+	"github.com/ca-risken/go-risken"
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestProjectHandler returns a fake RISKEN API handler for Signin and ListProject.
+func newTestProjectHandler(signinCount *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/signin":
+			atomic.AddInt32(signinCount, 1)
+			_, _ = w.Write([]byte(`{"project_id":1}`))
+		case "/api/v1/project/list-project":
+			_, _ = w.Write([]byte(`{"data":{"project":[{"project_id":1,"name":"test-project"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestGetCurrentProjectCache(t *testing.T) {
+	var signinCount int32
+	client := newTestRISKENClient(t, newTestProjectHandler(&signinCount))
+	s := newTestServer(client, nil)
+	ctx := context.Background()
+
+	for range 3 {
+		p, err := s.GetCurrentProject(ctx, client)
+		if err != nil {
+			t.Fatalf("GetCurrentProject() error = %v", err)
+		}
+		if p.ProjectId != 1 {
+			t.Errorf("GetCurrentProject() project_id = %d, want 1", p.ProjectId)
+		}
+	}
+	if signinCount != 1 {
+		t.Errorf("signin count = %d, want 1 (cached)", signinCount)
+	}
+
+	// Other errors keep the cache
+	s.invalidateOnAuthError(ctx, client, errors.New("some error"))
+	if _, err := s.GetCurrentProject(ctx, client); err != nil {
+		t.Fatalf("GetCurrentProject() error = %v", err)
+	}
+	if signinCount != 1 {
+		t.Errorf("signin count = %d, want 1 after non-auth error", signinCount)
+	}
+
+	// Auth errors invalidate the cache
+	s.invalidateOnAuthError(ctx, client, risken.APIError{Status: http.StatusUnauthorized})
+	if _, err := s.GetCurrentProject(ctx, client); err != nil {
+		t.Fatalf("GetCurrentProject() error = %v", err)
+	}
+	if signinCount != 2 {
+		t.Errorf("signin count = %d, want 2 after auth error", signinCount)
+	}
+}
+
+func TestGetCurrentProjectCacheKey(t *testing.T) {
+	var signinCount int32
+	handler := newTestProjectHandler(&signinCount)
+	s := newTestServer(nil, nil)
+
+	// Different client instances with the same key share the cache
+	for range 2 {
+		client := newTestRISKENClient(t, handler)
+		ctx := WithRISKENClientKey(context.Background(), "token-hash")
+		if _, err := s.GetCurrentProject(ctx, client); err != nil {
+			t.Fatalf("GetCurrentProject() error = %v", err)
+		}
+	}
+	if signinCount != 1 {
+		t.Errorf("signin count = %d, want 1", signinCount)
+	}
+}
+
+func TestSummarizeProject(t *testing.T) {
+	client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v1/finding/list-finding":
+			if q.Get("data_source") != "" || q.Get("from_score") != "" {
+				_, _ = w.Write([]byte(`{"data":{"finding_id":[],"total":0}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"finding_id":[],"total":3}}`))
+		case "/api/v1/alert/list-alert":
+			if q.Get("status") != "1" {
+				t.Errorf("list-alert status = %s, want 1", q.Get("status"))
+			}
+			_, _ = w.Write([]byte(`{"data":{"alert":[{"alert_id":1,"severity":"high"},{"alert_id":2,"severity":"high"},{"alert_id":3,"severity":"low"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	s := newTestServer(client, nil)
+
+	got, err := s.summarizeProject(context.Background(), client, &project.Project{ProjectId: 1, Name: "test-project"})
+	if err != nil {
+		t.Fatalf("summarizeProject() error = %v", err)
+	}
+	if got.Findings.Total != 3 || got.Findings.ByScore.Low != 3 {
+		t.Errorf("summarizeProject() findings = %+v, want 3 low findings", got.Findings.ByScore)
+	}
+	if diff := cmp.Diff(map[string]int{"total": 3, "high": 2, "low": 1}, got.ActiveAlerts); diff != "" {
+		t.Errorf("summarizeProject() active alerts mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,171 @@
+package riskenmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ca-risken/core/proto/project"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// digestDays is the period of weekly_security_digest.
+const digestDays = 7
+
+func (s *Server) TriageActiveFindingsPrompt() (mcp.Prompt, server.PromptHandlerFunc) {
+	const description = "Triage the active high score findings of the project and decide the next action of each finding."
+	return mcp.NewPrompt("triage_active_findings",
+			mcp.WithPromptDescription(description),
+		),
+		func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			p, err := s.getPromptProject(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return newPromptResult(description, p, `Triage the active findings of the project.
+
+1. Call summarize_findings to get the overview of the active findings by score band and data source.
+2. Call search_finding with from_score=0.8, status=1, sort=score, direction=desc, data_format=markdown and limit=20 to get the critical findings.
+   If there are fewer than 5 critical findings, also call it with from_score=0.6 and to_score=0.8 for the high findings.
+3. For each finding, call get_finding_recommendation to understand the risk and the fix.
+   Group the findings that share the same resource or the same root cause.
+4. Classify each finding (or group) into one of the following actions:
+   - Fix now: a real and exploitable risk.
+   - Assign: needs an owner. Propose a tag such as "owner:<team>" or "ticket:<id>" for tag_finding.
+   - Accept the risk: propose archive_finding with reason=risk_accepted and an expiry.
+   - False positive: propose archive_finding with reason=false_positive.
+5. Report a table of finding_id, resource_name, score, action and the reason of the action, most urgent first.
+
+Do not call tag_finding, archive_finding or bulk_archive_findings until the user confirms the proposed actions.`), nil
+		}
+}
+
+func (s *Server) WeeklySecurityDigestPrompt() (mcp.Prompt, server.PromptHandlerFunc) {
+	const description = "Write the weekly security digest of the project: trend of the findings, new critical findings and active alerts."
+	return mcp.NewPrompt("weekly_security_digest",
+			mcp.WithPromptDescription(description),
+		),
+		func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			p, err := s.getPromptProject(ctx)
+			if err != nil {
+				return nil, err
+			}
+			today := time.Now().UTC()
+			from := today.AddDate(0, 0, -digestDays).Format(reportDateFormat)
+			return newPromptResult(description, p, fmt.Sprintf(`Write the weekly security digest of the project for %[1]s to %[2]s.
+
+1. Call finding_report with from_date=%[1]s, to_date=%[2]s, interval=day and trend=true to get the trend of the finding counts.
+2. Call summarize_findings to get the current counts by score band and data source, and the top risky resources.
+3. Call search_finding with from_at=%[1]s, from_score=0.8, sort=updated_at, direction=desc and fields=finding_id,score,data_source,resource_name,description
+   to get the critical findings updated in the week.
+4. Call search_alert with status=1 to get the active alerts.
+
+Write the digest in Markdown with the following sections:
+- Summary: 3 bullet points for the executives. Say whether the risk is going up or down with the numbers.
+- Trend: the change of the total and each score band, and the data sources that changed the most.
+- New critical findings: a table of finding_id, resource_name and description. At most 10 rows.
+- Active alerts: a table of alert_id, severity and description.
+- Next actions: the top 3 actions for the next week.
+
+Use only the numbers returned by the tools. If a tool returns no data, say so instead of guessing.`, from, today.Format(reportDateFormat))), nil
+		}
+}
+
+func (s *Server) InvestigateAlertPrompt() (mcp.Prompt, server.PromptHandlerFunc) {
+	const description = "Investigate an alert: why it was triggered, the related findings and how to resolve it."
+	return mcp.NewPrompt("investigate_alert",
+			mcp.WithPromptDescription(description),
+			mcp.WithArgument("alert_id",
+				mcp.ArgumentDescription("Alert ID."),
+				mcp.RequiredArgument(),
+			),
+		),
+		func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			alertID, err := strconv.ParseUint(strings.TrimSpace(req.Params.Arguments["alert_id"]), 10, 32)
+			if err != nil || alertID == 0 {
+				return nil, fmt.Errorf("invalid alert_id: %q", req.Params.Arguments["alert_id"])
+			}
+			p, err := s.getPromptProject(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return newPromptResult(description, p, fmt.Sprintf(`Investigate the alert (alert_id=%[1]d).
+
+1. Call get_alert with alert_id=%[1]d to get the alert, its history, the related findings and the alert condition and rules that triggered it.
+2. Call search_finding with alert_id=%[1]d and data_format=markdown to get all the related findings. Follow next_cursor if there are more.
+3. For the highest score findings, call get_finding_recommendation to understand the risk and the fix.
+4. Explain the following:
+   - Why the alert was triggered: which rules matched which findings, and since when (from the history).
+   - Impact: the affected resources and what an attacker could do.
+   - Whether it is a true positive. If you are not sure, say what to check.
+5. Propose how to resolve it: the fix of each finding, and whether to keep the alert active, pend it (pend_alert) or close it (deactivate_alert).
+   If the rules are too noisy, propose the change of the alert condition or rules and check it with simulate_alert_condition.
+
+Do not call pend_alert, deactivate_alert or change the alert condition and rules until the user confirms.`, alertID)), nil
+		}
+}
+
+func (s *Server) RemediationPlanPrompt() (mcp.Prompt, server.PromptHandlerFunc) {
+	const description = "Make the remediation plan of a resource from its active findings and their recommendations."
+	return mcp.NewPrompt("remediation_plan",
+			mcp.WithPromptDescription(description),
+			mcp.WithArgument("resource_name",
+				mcp.ArgumentDescription("RISKEN ResourceName. e.g. \"arn:aws:iam::123456789012:user/test-user\", \"github/my-org/my-repo\" ..."),
+				mcp.RequiredArgument(),
+			),
+		),
+		func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			resourceName := strings.TrimSpace(req.Params.Arguments["resource_name"])
+			if resourceName == "" {
+				return nil, errors.New("resource_name is required")
+			}
+			p, err := s.getPromptProject(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return newPromptResult(description, p, fmt.Sprintf(`Make the remediation plan of the resource %[1]q.
+
+1. Call list_resources with resource_name=[%[1]q] to find the resource, and call get_resource with its resource_id to get its tags and findings.
+   If no resource is found, tell the user and stop.
+2. Call search_finding with resource_name=[%[1]q], from_score=0.0, status=1, fetch_all=true and data_format=markdown to get all the active findings of the resource.
+3. For each finding with score 0.3 or higher, call get_finding_recommendation.
+4. Group the findings by root cause (e.g. the same misconfiguration or the same outdated package), so that one fix resolves several findings.
+5. Write the plan in Markdown:
+   - Overview: the resource, the number of the findings by score band and the biggest risk.
+   - Steps: ordered by risk reduction per effort. For each step, the fix (with commands or configuration if the recommendation has them),
+     the finding IDs resolved by it, the effort (S/M/L) and how to verify the fix.
+   - Accepted risks: the findings that can be accepted, with the reason.
+6. Propose tags like "ticket:<id>" for tag_finding to track the steps.
+
+Do not call tag_finding or archive_finding until the user confirms.`, resourceName)), nil
+		}
+}
+
+// getPromptProject returns the current project to embed in the prompts.
+func (s *Server) getPromptProject(ctx context.Context) (*project.Project, error) {
+	riskenClient, err := s.GetRISKENClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RISKEN client: %w", err)
+	}
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	return p, nil
+}
+
+// newPromptResult returns the prompt with the project context and the instructions as a user message.
+func newPromptResult(description string, p *project.Project, instructions string) *mcp.GetPromptResult {
+	text := fmt.Sprintf(`You are a security analyst using the RISKEN MCP tools.
+RISKEN project: %s (project_id=%d). All the tools work on this project.
+Scores are 0.0 to 1.0 (low: 0.0~0.3, medium: 0.3~0.6, high: 0.6~0.8, critical: 0.8~1.0).
+
+%s`, p.Name, p.ProjectId, instructions)
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	})
+}
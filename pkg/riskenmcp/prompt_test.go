@@ -0,0 +1,84 @@
+package riskenmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestPrompts(t *testing.T) {
+	var signinCount int32
+	client := newTestRISKENClient(t, newTestProjectHandler(&signinCount))
+	s := newTestServer(client, nil)
+
+	tests := []struct {
+		name         string
+		prompt       func() (mcp.Prompt, server.PromptHandlerFunc)
+		args         map[string]string
+		wantContains []string
+		wantErr      bool
+	}{
+		{
+			name:         "triage_active_findings",
+			prompt:       s.TriageActiveFindingsPrompt,
+			wantContains: []string{"summarize_findings", "get_finding_recommendation", "until the user confirms"},
+		},
+		{
+			name:         "weekly_security_digest",
+			prompt:       s.WeeklySecurityDigestPrompt,
+			wantContains: []string{"finding_report with from_date=", "search_alert with status=1"},
+		},
+		{
+			name:         "investigate_alert",
+			prompt:       s.InvestigateAlertPrompt,
+			args:         map[string]string{"alert_id": "123"},
+			wantContains: []string{"get_alert with alert_id=123", "search_finding with alert_id=123"},
+		},
+		{
+			name:    "investigate_alert without alert_id",
+			prompt:  s.InvestigateAlertPrompt,
+			args:    map[string]string{"alert_id": "abc"},
+			wantErr: true,
+		},
+		{
+			name:         "remediation_plan",
+			prompt:       s.RemediationPlanPrompt,
+			args:         map[string]string{"resource_name": "github/my-org/app"},
+			wantContains: []string{`resource_name=["github/my-org/app"]`, "fetch_all=true"},
+		},
+		{
+			name:    "remediation_plan without resource_name",
+			prompt:  s.RemediationPlanPrompt,
+			args:    map[string]string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt, handler := tt.prompt()
+			req := mcp.GetPromptRequest{}
+			req.Params.Name = prompt.Name
+			req.Params.Arguments = tt.args
+			got, err := handler(context.Background(), req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got.Messages) != 1 || got.Messages[0].Role != mcp.RoleUser {
+				t.Fatalf("handler() messages = %+v, want one user message", got.Messages)
+			}
+			text := got.Messages[0].Content.(mcp.TextContent).Text
+			for _, want := range append(tt.wantContains, "RISKEN project: test-project (project_id=1)") {
+				if !strings.Contains(text, want) {
+					t.Errorf("handler() message does not contain %q:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
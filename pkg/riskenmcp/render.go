@@ -0,0 +1,443 @@
+package riskenmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Output formats of the tool results
+const (
+	renderFormatJSON     = "json"
+	renderFormatCompact  = "compact"
+	renderFormatMarkdown = "markdown"
+)
+
+// renderOptions is the projection and format of a tool result.
+type renderOptions struct {
+	Fields []string
+	Format string
+}
+
+// withRenderer adds the fields and format params to the tool, and renders the JSON result of the handler with them.
+// Error results and plain text results are returned as is.
+func withRenderer(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	mcp.WithString(
+		"fields",
+		mcp.Description("Comma separated fields to return for each record, e.g. \"finding_id,score,resource_name,description\". "+
+			"The other fields (e.g. large finding data) are omitted. (default: all fields)"),
+	)(&tool)
+	mcp.WithString(
+		"format",
+		mcp.Description("Output format. json: JSON, compact: plain text with tab separated tables, markdown: Markdown with tables."),
+		mcp.Enum(renderFormatJSON, renderFormatCompact, renderFormatMarkdown),
+		mcp.DefaultString(renderFormatJSON),
+	)(&tool)
+	return tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		opts, err := parseRenderOptions(req)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to parse params: %s", err)), nil
+		}
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError || opts == nil {
+			return result, err
+		}
+		for i, c := range result.Content {
+			text, ok := c.(mcp.TextContent)
+			if !ok {
+				continue
+			}
+			rendered, err := opts.render(text.Text)
+			if err != nil {
+				continue // not JSON
+			}
+			text.Text = rendered
+			result.Content[i] = text
+		}
+		return result, nil
+	}
+}
+
+// parseRenderOptions returns nil if the result does not need to be rendered.
+func parseRenderOptions(req mcp.CallToolRequest) (*renderOptions, error) {
+	opts := &renderOptions{Format: renderFormatJSON}
+	fields, err := helper.ParseMCPArgs[string]("fields", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("fields error: %s", err)
+	}
+	if fields != nil {
+		for _, f := range strings.Split(*fields, ",") {
+			if f = strings.TrimSpace(f); f != "" && !slices.Contains(opts.Fields, f) {
+				opts.Fields = append(opts.Fields, f)
+			}
+		}
+	}
+	format, err := helper.ParseMCPArgs[string]("format", req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("format error: %s", err)
+	}
+	if format != nil {
+		switch *format {
+		case renderFormatJSON, renderFormatCompact, renderFormatMarkdown:
+			opts.Format = *format
+		default:
+			return nil, fmt.Errorf("format must be one of %s, %s, %s: %s", renderFormatJSON, renderFormatCompact, renderFormatMarkdown, *format)
+		}
+	}
+	if len(opts.Fields) == 0 && opts.Format == renderFormatJSON {
+		return nil, nil
+	}
+	return opts, nil
+}
+
+// render projects and formats the JSON text.
+func (o *renderOptions) render(text string) (string, error) {
+	v, err := decodeOrderedJSON(text)
+	if err != nil {
+		return "", err
+	}
+	if len(o.Fields) > 0 {
+		v = projectFields(v, o.Fields)
+	}
+	switch o.Format {
+	case renderFormatCompact:
+		return renderCompact(v), nil
+	case renderFormatMarkdown:
+		return renderMarkdown(v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// jsonObject is a JSON object that keeps the order of the keys.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeOrderedJSON decodes the JSON text into *jsonObject, []any and scalar values (json.Number for numbers).
+func decodeOrderedJSON(text string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	v, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+	switch delim {
+	case '{':
+		obj := &jsonObject{values: map[string]any{}}
+		for dec.More() {
+			keyToken, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyToken.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object key: %v", keyToken)
+			}
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := obj.values[key]; !ok {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = value
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case '[':
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	default:
+		return nil, fmt.Errorf("unexpected delimiter: %v", delim)
+	}
+}
+
+// errorField is kept in the projected records, so that a failed record (e.g. FindingError) keeps its error message.
+const errorField = "error"
+
+// projectFields keeps only the fields in the records. An object that has any of the fields is a record,
+// and the other objects (e.g. the response envelope with total and offset) are kept with their values projected.
+func projectFields(v any, fields []string) any {
+	switch t := v.(type) {
+	case *jsonObject:
+		projected := &jsonObject{values: map[string]any{}}
+		for _, f := range fields {
+			if value, ok := t.values[f]; ok {
+				projected.keys = append(projected.keys, f)
+				projected.values[f] = value
+			}
+		}
+		if len(projected.keys) > 0 {
+			if value, ok := t.values[errorField]; ok && !slices.Contains(projected.keys, errorField) {
+				projected.keys = append(projected.keys, errorField)
+				projected.values[errorField] = value
+			}
+			return projected
+		}
+		for _, k := range t.keys {
+			projected.keys = append(projected.keys, k)
+			projected.values[k] = projectFields(t.values[k], fields)
+		}
+		return projected
+	case []any:
+		arr := make([]any, len(t))
+		for i, item := range t {
+			arr[i] = projectFields(item, fields)
+		}
+		return arr
+	default:
+		return v
+	}
+}
+
+// renderCompact renders the value as plain text. The arrays of objects are rendered as tab separated tables.
+func renderCompact(v any) string {
+	var b strings.Builder
+	switch t := v.(type) {
+	case *jsonObject:
+		writeCompactObject(&b, t, "")
+	case []any:
+		writeCompactArray(&b, "", t, "")
+	default:
+		b.WriteString(formatScalar(v))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func writeCompactObject(b *strings.Builder, obj *jsonObject, indent string) {
+	for _, k := range obj.keys {
+		switch t := obj.values[k].(type) {
+		case *jsonObject:
+			fmt.Fprintf(b, "%s%s:\n", indent, k)
+			writeCompactObject(b, t, indent+"  ")
+		case []any:
+			writeCompactArray(b, k, t, indent)
+		default:
+			fmt.Fprintf(b, "%s%s: %s\n", indent, k, formatCell(t))
+		}
+	}
+}
+
+func writeCompactArray(b *strings.Builder, key string, arr []any, indent string) {
+	columns, ok := tableColumns(arr)
+	if !ok {
+		values := make([]string, len(arr))
+		for i, item := range arr {
+			values[i] = formatCell(item)
+		}
+		if key == "" {
+			fmt.Fprintf(b, "%s%s\n", indent, strings.Join(values, ", "))
+			return
+		}
+		fmt.Fprintf(b, "%s%s: [%s]\n", indent, key, strings.Join(values, ", "))
+		return
+	}
+	if key != "" {
+		fmt.Fprintf(b, "%s%s (%d):\n", indent, key, len(arr))
+		indent += "  "
+	}
+	fmt.Fprintf(b, "%s%s\n", indent, strings.Join(columns, "\t"))
+	for _, item := range arr {
+		obj := item.(*jsonObject)
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = strings.ReplaceAll(formatCell(obj.values[c]), "\t", " ")
+		}
+		fmt.Fprintf(b, "%s%s\n", indent, strings.Join(cells, "\t"))
+	}
+}
+
+// renderMarkdown renders the value as Markdown. The arrays of objects are rendered as tables.
+func renderMarkdown(v any) string {
+	var b strings.Builder
+	switch t := v.(type) {
+	case *jsonObject:
+		writeMarkdownObject(&b, t, 2)
+	case []any:
+		writeMarkdownArray(&b, "", t, 2)
+	default:
+		b.WriteString(formatScalar(v))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func writeMarkdownObject(b *strings.Builder, obj *jsonObject, level int) {
+	// Write the scalar values first so that they are not placed under the headings of the nested values.
+	for _, k := range obj.keys {
+		switch t := obj.values[k].(type) {
+		case *jsonObject:
+		case []any:
+			if _, ok := tableColumns(t); !ok {
+				writeMarkdownArray(b, k, t, level)
+			}
+		default:
+			fmt.Fprintf(b, "- **%s**: %s\n", k, escapeMarkdown(formatCell(t)))
+		}
+	}
+	for _, k := range obj.keys {
+		switch t := obj.values[k].(type) {
+		case *jsonObject:
+			fmt.Fprintf(b, "\n%s %s\n\n", markdownHeading(level), k)
+			writeMarkdownObject(b, t, level+1)
+		case []any:
+			if _, ok := tableColumns(t); ok {
+				writeMarkdownArray(b, k, t, level)
+			}
+		}
+	}
+}
+
+func writeMarkdownArray(b *strings.Builder, key string, arr []any, level int) {
+	columns, ok := tableColumns(arr)
+	if !ok {
+		values := make([]string, len(arr))
+		for i, item := range arr {
+			values[i] = escapeMarkdown(formatCell(item))
+		}
+		if key == "" {
+			for _, v := range values {
+				fmt.Fprintf(b, "- %s\n", v)
+			}
+			return
+		}
+		fmt.Fprintf(b, "- **%s**: %s\n", key, strings.Join(values, ", "))
+		return
+	}
+	if key != "" {
+		fmt.Fprintf(b, "\n%s %s (%d)\n\n", markdownHeading(level), key, len(arr))
+	}
+	fmt.Fprintf(b, "| %s |\n", strings.Join(columns, " | "))
+	fmt.Fprintf(b, "|%s\n", strings.Repeat(" --- |", len(columns)))
+	for _, item := range arr {
+		obj := item.(*jsonObject)
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = escapeMarkdown(formatCell(obj.values[c]))
+		}
+		fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+	}
+}
+
+func markdownHeading(level int) string {
+	return strings.Repeat("#", min(level, 6))
+}
+
+// tableColumns returns the keys of the objects in the order of appearance.
+// It returns false if the array is empty or has a non-object item.
+func tableColumns(arr []any) ([]string, bool) {
+	if len(arr) == 0 {
+		return nil, false
+	}
+	columns := []string{}
+	for _, item := range arr {
+		obj, ok := item.(*jsonObject)
+		if !ok {
+			return nil, false
+		}
+		for _, k := range obj.keys {
+			if !slices.Contains(columns, k) {
+				columns = append(columns, k)
+			}
+		}
+	}
+	return columns, true
+}
+
+// formatCell formats the value in a line. The nested objects and arrays are formatted as JSON.
+func formatCell(v any) string {
+	switch v.(type) {
+	case *jsonObject, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	default:
+		return strings.Join(strings.Fields(formatScalar(v)), " ")
+	}
+}
+
+func formatScalar(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,146 @@
+package riskenmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRenderOptions(t *testing.T) {
+	const text = `{"findings":[{"finding_id":1,"score":0.8,"resource_name":"arn:aws:s3:::a|b","data":{"k":"v"}},{"finding_id":2,"score":0.3,"resource_name":"repo","data":{}}],"total":2,"tags":["x","y"]}`
+	tests := []struct {
+		name string
+		text string // default: text
+		args map[string]any
+		want string
+	}{
+		{
+			name: "fields",
+			args: map[string]any{"fields": "finding_id, score"},
+			want: `{"findings":[{"finding_id":1,"score":0.8},{"finding_id":2,"score":0.3}],"total":2,"tags":["x","y"]}`,
+		},
+		{
+			name: "fields keep error",
+			text: `{"findings":[{"finding_id":1,"score":0.8}],"errors":[{"finding_id":3,"error":"not found"}]}`,
+			args: map[string]any{"fields": "finding_id"},
+			want: `{"findings":[{"finding_id":1}],"errors":[{"finding_id":3,"error":"not found"}]}`,
+		},
+		{
+			name: "compact",
+			args: map[string]any{"fields": "resource_name,finding_id", "format": "compact"},
+			want: "findings (2):\n" +
+				"  resource_name\tfinding_id\n" +
+				"  arn:aws:s3:::a|b\t1\n" +
+				"  repo\t2\n" +
+				"total: 2\n" +
+				"tags: [x, y]",
+		},
+		{
+			name: "markdown",
+			args: map[string]any{"format": "markdown"},
+			want: "- **total**: 2\n" +
+				"- **tags**: x, y\n" +
+				"\n" +
+				"## findings (2)\n" +
+				"\n" +
+				"| finding_id | score | resource_name | data |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| 1 | 0.8 | arn:aws:s3:::a\\|b | {\"k\":\"v\"} |\n" +
+				"| 2 | 0.3 | repo | {} |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			opts, err := parseRenderOptions(req)
+			if err != nil {
+				t.Fatalf("parseRenderOptions() error = %v", err)
+			}
+			input := text
+			if tt.text != "" {
+				input = tt.text
+			}
+			got, err := opts.render(input)
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("render() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithRenderer(t *testing.T) {
+	tool, handler := withRenderer(mcp.NewTool("test"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch req.GetArguments()["result"] {
+		case "text":
+			return mcp.NewToolResultText("Successfully done"), nil
+		case "error":
+			return mcp.NewToolResultError(`{"error":"failed"}`), nil
+		default:
+			return mcp.NewToolResultText(`{"alert_id":1,"description":"test"}`), nil
+		}
+	})
+	if _, ok := tool.InputSchema.Properties["fields"]; !ok {
+		t.Error("withRenderer() did not add the fields param")
+	}
+	if _, ok := tool.InputSchema.Properties["format"]; !ok {
+		t.Error("withRenderer() did not add the format param")
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		want      string
+		wantError bool
+	}{
+		{
+			name: "default",
+			args: map[string]any{},
+			want: `{"alert_id":1,"description":"test"}`,
+		},
+		{
+			name: "fields",
+			args: map[string]any{"fields": "description"},
+			want: `{"description":"test"}`,
+		},
+		{
+			name: "plain text",
+			args: map[string]any{"result": "text", "format": "markdown"},
+			want: "Successfully done",
+		},
+		{
+			name:      "error result",
+			args:      map[string]any{"result": "error", "fields": "description"},
+			want:      `{"error":"failed"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid format",
+			args:      map[string]any{"format": "yaml"},
+			want:      "failed to parse params: format must be one of json, compact, markdown: yaml",
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handler() error = %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Errorf("handler() IsError = %v, want %v", result.IsError, tt.wantError)
+			}
+			if diff := cmp.Diff(tt.want, result.Content[0].(mcp.TextContent).Text); diff != "" {
+				t.Errorf("handler() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
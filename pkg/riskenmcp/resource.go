@@ -0,0 +1,48 @@
+package riskenmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ca-risken/core/proto/project"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// parseResourceID parses the numeric URI template variable of the resource request.
+func parseResourceID(key string, request mcp.ReadResourceRequest) (uint64, error) {
+	value, ok := helper.ParseResourceURIArgs(key, request.Params.Arguments)
+	if !ok || value == "" {
+		return 0, fmt.Errorf("%s is required", key)
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return id, nil
+}
+
+// getResourceProject returns the current project after checking that it is the project_id of the resource URI.
+// RISKEN access tokens are issued per project, so the resources of another project cannot be read.
+func (s *Server) getResourceProject(ctx context.Context, riskenClient *risken.Client, request mcp.ReadResourceRequest) (*project.Project, error) {
+	projectID, err := parseResourceID("project_id", request)
+	if err != nil {
+		return nil, err
+	}
+	return s.getURIProject(ctx, riskenClient, projectID)
+}
+
+// getURIProject returns the current project after checking that it is the project_id in the URI.
+func (s *Server) getURIProject(ctx context.Context, riskenClient *risken.Client, projectID uint64) (*project.Project, error) {
+	p, err := s.GetCurrentProject(ctx, riskenClient)
+	if err != nil {
+		return nil, errors.New("failed to get project")
+	}
+	if uint64(p.ProjectId) != projectID {
+		return nil, fmt.Errorf("project_id=%d in the URI is not the authenticated project (project_id=%d). The access token can read only its own project", projectID, p.ProjectId)
+	}
+	return p, nil
+}
@@ -0,0 +1,148 @@
+package riskenmcp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestFindingResourceContentsHandlerProjectID(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       map[string]any
+		wantErr    string
+		wantGetHit bool
+	}{
+		{
+			name:       "authenticated project",
+			args:       map[string]any{"project_id": []string{"1"}, "finding_id": []string{"10"}},
+			wantGetHit: true,
+		},
+		{
+			name:    "another project",
+			args:    map[string]any{"project_id": []string{"999"}, "finding_id": []string{"10"}},
+			wantErr: "project_id=999 in the URI is not the authenticated project (project_id=1)",
+		},
+		{
+			name:    "no project_id",
+			args:    map[string]any{"finding_id": []string{"10"}},
+			wantErr: "project_id is required",
+		},
+		{
+			name:    "invalid project_id",
+			args:    map[string]any{"project_id": "abc", "finding_id": []string{"10"}},
+			wantErr: "invalid project_id: abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signinCount int32
+			projectHandler := newTestProjectHandler(&signinCount)
+			getHit := false
+			client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/finding/get-finding" {
+					projectHandler(w, r)
+					return
+				}
+				getHit = true
+				if r.URL.Query().Get("project_id") != "1" {
+					t.Errorf("get-finding project_id = %s, want 1", r.URL.Query().Get("project_id"))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data":{"finding":{"finding_id":10,"project_id":1}}}`))
+			})
+			s := newTestServer(client, nil)
+
+			req := mcp.ReadResourceRequest{}
+			req.Params.URI = "finding://x/10"
+			req.Params.Arguments = tt.args
+			contents, err := s.FindingResourceContentsHandler()(context.Background(), req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("handler() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("handler() error = %v", err)
+			} else if len(contents) == 0 {
+				t.Error("handler() returned no contents")
+			}
+			if getHit != tt.wantGetHit {
+				t.Errorf("GetFinding called = %v, want %v", getHit, tt.wantGetHit)
+			}
+		})
+	}
+}
+
+func TestParseResourceID(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    uint64
+		wantErr string
+	}{
+		{
+			name: "uri template argument",
+			args: map[string]any{"finding_id": []string{"10"}},
+			want: 10,
+		},
+		{
+			name: "string argument",
+			args: map[string]any{"finding_id": "10"},
+			want: 10,
+		},
+		{
+			name:    "empty uri template argument",
+			args:    map[string]any{"finding_id": []string{}},
+			wantErr: "finding_id is required",
+		},
+		{
+			name:    "empty string",
+			args:    map[string]any{"finding_id": ""},
+			wantErr: "finding_id is required",
+		},
+		{
+			name:    "missing",
+			args:    map[string]any{},
+			wantErr: "finding_id is required",
+		},
+		{
+			name:    "unsupported type",
+			args:    map[string]any{"finding_id": 10},
+			wantErr: "finding_id is required",
+		},
+		{
+			name:    "not a number",
+			args:    map[string]any{"finding_id": []string{"abc"}},
+			wantErr: "invalid finding_id: abc",
+		},
+		{
+			name:    "negative",
+			args:    map[string]any{"finding_id": "-1"},
+			wantErr: "invalid finding_id: -1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.ReadResourceRequest{}
+			req.Params.Arguments = tt.args
+			got, err := parseResourceID("finding_id", req)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseResourceID() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseResourceID() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseResourceID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,55 +3,113 @@ package riskenmcp
 import (
 	"log/slog"
 
+	"github.com/ca-risken/core/proto/project"
 	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 type Server struct {
-	MCPServer    *server.MCPServer
-	riskenClient *risken.Client
-	logger       *slog.Logger
+	MCPServer        *server.MCPServer
+	riskenClient     *risken.Client
+	config           *Config
+	logger           *slog.Logger
+	projectCache     *helper.TTLCache[*project.Project]
+	signer           *tokenSigner
+	subscriptions    *subscriptionManager
+	bulkArchivePlans *helper.TTLCache[*bulkArchivePlan]
 }
 
-func NewServer(riskenClient *risken.Client, name, version string, logger *slog.Logger, opts ...server.ServerOption) *Server {
+func NewServer(riskenClient *risken.Client, name, version string, config *Config, logger *slog.Logger, opts ...server.ServerOption) *Server {
 	// Create a new MCP server
-	opts = addOpts(opts...)
+	hooks := &server.Hooks{}
+	opts = addOpts(hooks, opts...)
 	s := server.NewMCPServer(name, version, opts...)
-	mcpserver := createRISKENMCPServer(s, riskenClient, logger)
+	mcpserver := createRISKENMCPServer(s, hooks, riskenClient, config, logger)
 	return mcpserver
 }
 
-func NewServerForMultiProject(name, version string, logger *slog.Logger, opts ...server.ServerOption) *Server {
+func NewServerForMultiProject(name, version string, config *Config, logger *slog.Logger, opts ...server.ServerOption) *Server {
 	// Create a new MCP server
-	opts = addOpts(opts...)
+	hooks := &server.Hooks{}
+	opts = addOpts(hooks, opts...)
 	s := server.NewMCPServer(name, version, opts...)
 	mcpserver := createRISKENMCPServer(
 		s,
+		hooks,
 		nil, // dynamic generate RISKEN client per request
+		config,
 		logger,
 	)
 	return mcpserver
 }
 
-func addOpts(opts ...server.ServerOption) []server.ServerOption {
+func addOpts(hooks *server.Hooks, opts ...server.ServerOption) []server.ServerOption {
 	defaultOpts := []server.ServerOption{
 		server.WithResourceCapabilities(true, true),
 		server.WithRecovery(),
+		server.WithHooks(hooks),
 	}
 	opts = append(defaultOpts, opts...)
 	return opts
 }
 
-func createRISKENMCPServer(s *server.MCPServer, riskenClient *risken.Client, logger *slog.Logger) *Server {
+func createRISKENMCPServer(s *server.MCPServer, hooks *server.Hooks, riskenClient *risken.Client, config *Config, logger *slog.Logger) *Server {
 	mcpserver := &Server{
 		MCPServer:    s,
 		riskenClient: riskenClient,
+		config:       config.withDefaults(),
 		logger:       logger,
 	}
+	mcpserver.projectCache = helper.NewTTLCache[*project.Project](mcpserver.config.ProjectCacheTTL, defaultProjectCacheSize)
+	mcpserver.signer = newTokenSigner(mcpserver.config.SigningKey)
+	mcpserver.bulkArchivePlans = helper.NewTTLCache[*bulkArchivePlan](bulkArchiveTokenTTL, bulkArchiveMaxPlans)
+	mcpserver.subscriptions = newSubscriptionManager(func(sessionID, uri string) error {
+		return s.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+	})
+	hooks.AddOnRegisterSession(mcpserver.subscriptions.registerSession)
+	hooks.AddOnUnregisterSession(mcpserver.subscriptions.unregisterSession)
 	s.AddResourceTemplate(mcpserver.GetFindingResource())
-	s.AddTool(mcpserver.GetProject())
-	s.AddTool(mcpserver.SearchFinding())
-	s.AddTool(mcpserver.ArchiveFinding())
-	s.AddTool(mcpserver.SearchAlert())
+	s.AddResourceTemplate(mcpserver.GetFindingRecommendationResource())
+	s.AddResourceTemplate(mcpserver.GetResourceResource())
+	s.AddResourceTemplate(mcpserver.GetProjectResource())
+	s.AddResourceTemplate(mcpserver.GetProjectSummaryResource())
+	s.AddResourceTemplate(mcpserver.GetAlertResource())
+	s.AddResource(mcpserver.GetActiveAlertsResource())
+	s.AddResource(mcpserver.GetActiveHighAlertsResource())
+	s.AddPrompt(mcpserver.TriageActiveFindingsPrompt())
+	s.AddPrompt(mcpserver.WeeklySecurityDigestPrompt())
+	s.AddPrompt(mcpserver.InvestigateAlertPrompt())
+	s.AddPrompt(mcpserver.RemediationPlanPrompt())
+	s.AddTool(withRenderer(mcpserver.GetProject()))
+	s.AddTool(withRenderer(mcpserver.SearchFinding()))
+	s.AddTool(withRenderer(mcpserver.SummarizeFindings()))
+	s.AddTool(withRenderer(mcpserver.FindingReport()))
+	s.AddTool(withRenderer(mcpserver.GetFindingRecommendation()))
+	s.AddTool(withRenderer(mcpserver.ListFindingTags()))
+	s.AddTool(withRenderer(mcpserver.TagFinding()))
+	s.AddTool(withRenderer(mcpserver.UntagFinding()))
+	s.AddTool(withRenderer(mcpserver.ArchiveFinding()))
+	s.AddTool(withRenderer(mcpserver.BulkArchiveFindings()))
+	s.AddTool(withRenderer(mcpserver.UnarchiveFinding()))
+	s.AddTool(withRenderer(mcpserver.GetArchiveInfo()))
+	s.AddTool(withRenderer(mcpserver.ListResources()))
+	s.AddTool(withRenderer(mcpserver.GetResource()))
+	s.AddTool(withRenderer(mcpserver.SearchAlert()))
+	s.AddTool(withRenderer(mcpserver.GetAlert()))
+	s.AddTool(withRenderer(mcpserver.PendAlert()))
+	s.AddTool(withRenderer(mcpserver.DeactivateAlert()))
+	s.AddTool(withRenderer(mcpserver.ListAlertConditions()))
+	s.AddTool(withRenderer(mcpserver.PutAlertCondition()))
+	s.AddTool(withRenderer(mcpserver.DeleteAlertCondition()))
+	s.AddTool(withRenderer(mcpserver.ListAlertRules()))
+	s.AddTool(withRenderer(mcpserver.PutAlertRule()))
+	s.AddTool(withRenderer(mcpserver.DeleteAlertRule()))
+	s.AddTool(withRenderer(mcpserver.AnalyzeAlert()))
+	s.AddTool(withRenderer(mcpserver.SimulateAlertCondition()))
+	s.AddTool(withRenderer(mcpserver.ListNotifications()))
+	s.AddTool(withRenderer(mcpserver.PutNotification()))
+	s.AddTool(withRenderer(mcpserver.TestNotification()))
 	return mcpserver
 }
@@ -0,0 +1,76 @@
+package riskenmcp
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// tokenClaims is the JWT claims of the tokens issued to MCP clients.
+type tokenClaims struct {
+	ProjectID uint32          `json:"project_id"`
+	Payload   json.RawMessage `json:"payload"`
+	jwt.RegisteredClaims
+}
+
+// tokenSigner issues and verifies the signed tokens handed to MCP clients (e.g. confirmation tokens).
+// The token is bound to the purpose and the project, so that it cannot be reused for another operation.
+type tokenSigner struct {
+	key []byte
+}
+
+// newTokenSigner creates a tokenSigner. A random key is generated if the key is empty,
+// so the issued tokens are valid only in this process.
+func newTokenSigner(key string) *tokenSigner {
+	if key != "" {
+		return &tokenSigner{key: []byte(key)}
+	}
+	randomKey := make([]byte, 32)
+	if _, err := rand.Read(randomKey); err != nil {
+		panic(fmt.Sprintf("failed to generate signing key: %s", err))
+	}
+	return &tokenSigner{key: randomKey}
+}
+
+// sign returns the signed token of the payload.
+func (t *tokenSigner) sign(purpose string, projectID uint32, payload any, expiresAt time.Time) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+		ProjectID: projectID,
+		Payload:   data,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   purpose,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	signed, err := token.SignedString(t.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// verify validates the token and decodes the payload into v.
+func (t *tokenSigner) verify(tokenString, purpose string, projectID uint32, v any) error {
+	claims := &tokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		return t.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithSubject(purpose), jwt.WithExpirationRequired())
+	if err != nil {
+		return fmt.Errorf("invalid token: %w", err)
+	}
+	if claims.ProjectID != projectID {
+		return errors.New("invalid token: the token was issued for another project")
+	}
+	if err := json.Unmarshal(claims.Payload, v); err != nil {
+		return fmt.Errorf("invalid token payload: %w", err)
+	}
+	return nil
+}
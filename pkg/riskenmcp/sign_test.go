@@ -0,0 +1,91 @@
+package riskenmcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenSigner(t *testing.T) {
+	type payload struct {
+		IDs []uint64 `json:"ids"`
+	}
+	signer := newTokenSigner("test-key")
+	token, err := signer.sign("test", 1, &payload{IDs: []uint64{1, 2}}, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("sign() error = %v", err)
+	}
+	expired, err := signer.sign("test", 1, &payload{}, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("sign() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		signer    *tokenSigner
+		token     string
+		purpose   string
+		projectID uint32
+		wantErr   bool
+	}{
+		{
+			name:      "valid",
+			signer:    signer,
+			token:     token,
+			purpose:   "test",
+			projectID: 1,
+		},
+		{
+			name:      "other purpose",
+			signer:    signer,
+			token:     token,
+			purpose:   "other",
+			projectID: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "other project",
+			signer:    signer,
+			token:     token,
+			purpose:   "test",
+			projectID: 2,
+			wantErr:   true,
+		},
+		{
+			name:      "other key",
+			signer:    newTokenSigner("other-key"),
+			token:     token,
+			purpose:   "test",
+			projectID: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "expired",
+			signer:    signer,
+			token:     expired,
+			purpose:   "test",
+			projectID: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "tampered",
+			signer:    signer,
+			token:     token + "x",
+			purpose:   "test",
+			projectID: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &payload{}
+			err := tt.signer.verify(tt.token, tt.purpose, tt.projectID, got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(got.IDs) != 2 {
+				t.Errorf("verify() payload = %v, want 2 ids", got.IDs)
+			}
+		})
+	}
+}
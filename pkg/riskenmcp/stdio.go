@@ -0,0 +1,94 @@
+package riskenmcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ServeStdio serves the MCP server on stdin and stdout until SIGTERM or SIGINT.
+// It works like server.ServeStdio, and also handles the resource subscriptions.
+func (s *Server) ServeStdio() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	return s.serveStdio(ctx, os.Stdin, os.Stdout)
+}
+
+func (s *Server) serveStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	stdioServer := server.NewStdioServer(s.MCPServer)
+	sessionCtx := make(chan context.Context, 1)
+	stdioServer.SetContextFunc(func(ctx context.Context) context.Context {
+		sessionCtx <- ctx
+		return ctx
+	})
+
+	out := &syncWriter{w: stdout}
+	in, pipe := io.Pipe()
+	go func() {
+		select {
+		case ctx := <-sessionCtx:
+			pipe.CloseWithError(s.filterSubscriptionMessages(ctx, stdin, pipe, out))
+		case <-ctx.Done():
+			pipe.CloseWithError(ctx.Err())
+		}
+	}()
+	return stdioServer.Listen(ctx, in, out)
+}
+
+// filterSubscriptionMessages handles the subscription messages in stdin, and passes the other messages to next.
+func (s *Server) filterSubscriptionMessages(ctx context.Context, stdin io.Reader, next, stdout io.Writer) error {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return errors.New("no stdio session found in context")
+	}
+	reader := bufio.NewReader(stdin)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if response, ok := s.HandleSubscriptionMessage(ctx, session.SessionID(), bytes.TrimSpace(line)); ok {
+				if err := writeStdioMessage(stdout, response); err != nil {
+					return err
+				}
+			} else if _, err := next.Write(line); err != nil {
+				return err
+			}
+		}
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return nil
+			}
+			return readErr
+		}
+	}
+}
+
+func writeStdioMessage(w io.Writer, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// syncWriter serializes the writes of the responses and the notifications.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
@@ -0,0 +1,356 @@
+package riskenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ca-risken/core/proto/alert"
+	"github.com/ca-risken/core/proto/finding"
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+
+	// mcpSessionIDHeader is the session ID header of the Streamable-HTTP transport.
+	mcpSessionIDHeader = "Mcp-Session-Id"
+
+	// maxSubscriptionsPerSession limits the RISKEN API calls of the polling per session.
+	maxSubscriptionsPerSession = 100
+)
+
+// watchedResourceKind is the URI scheme of the resources that can be subscribed.
+type watchedResourceKind string
+
+const (
+	watchedFinding watchedResourceKind = "finding"
+	watchedAlert   watchedResourceKind = "alert"
+)
+
+// watchedResource is a resource subscribed by a session.
+type watchedResource struct {
+	uri          string
+	kind         watchedResourceKind
+	projectID    uint32
+	id           uint64
+	riskenClient *risken.Client
+	state        resourceState
+}
+
+// resourceState is the part of a resource that is compared to detect its changes.
+type resourceState struct {
+	Score   float32
+	Status  string
+	Pending bool
+}
+
+// sessionSubscriptions is the subscribed resources of a session, polled until cancel is called.
+type sessionSubscriptions struct {
+	resources map[string]*watchedResource
+	cancel    context.CancelFunc
+}
+
+// subscriptionManager keeps the resources subscribed by the sessions.
+// mcp-go does not handle resources/subscribe, so the transports pass the messages to HandleSubscriptionMessage.
+type subscriptionManager struct {
+	notify func(sessionID, uri string) error
+
+	mu         sync.Mutex
+	registered map[string]bool
+	sessions   map[string]*sessionSubscriptions
+}
+
+func newSubscriptionManager(notify func(sessionID, uri string) error) *subscriptionManager {
+	return &subscriptionManager{
+		notify:     notify,
+		registered: map[string]bool{},
+		sessions:   map[string]*sessionSubscriptions{},
+	}
+}
+
+// registerSession is the OnRegisterSession hook. Only the registered sessions can receive notifications.
+func (m *subscriptionManager) registerSession(_ context.Context, session server.ClientSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.registered[session.SessionID()] = true
+}
+
+// unregisterSession is the OnUnregisterSession hook. It stops polling the resources of the session.
+func (m *subscriptionManager) unregisterSession(_ context.Context, session server.ClientSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.registered, session.SessionID())
+	if ss, ok := m.sessions[session.SessionID()]; ok {
+		ss.cancel()
+		delete(m.sessions, session.SessionID())
+	}
+}
+
+// subscribe adds the resource to the session.
+// It returns the context of the polling when the session has no other subscriptions, and nil otherwise.
+func (m *subscriptionManager) subscribe(sessionID string, r *watchedResource) (context.Context, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.registered[sessionID] {
+		return nil, fmt.Errorf("session %q has no open stream to receive notifications", sessionID)
+	}
+	if ss, ok := m.sessions[sessionID]; ok {
+		if _, ok := ss.resources[r.uri]; !ok && len(ss.resources) >= maxSubscriptionsPerSession {
+			return nil, fmt.Errorf("too many subscriptions: a session can subscribe up to %d resources", maxSubscriptionsPerSession)
+		}
+		ss.resources[r.uri] = r
+		return nil, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.sessions[sessionID] = &sessionSubscriptions{
+		resources: map[string]*watchedResource{r.uri: r},
+		cancel:    cancel,
+	}
+	return ctx, nil
+}
+
+// unsubscribe removes the resource from the session, and stops polling when no resource is left.
+func (m *subscriptionManager) unsubscribe(sessionID, uri string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ss, ok := m.sessions[sessionID]
+	if !ok {
+		return
+	}
+	delete(ss.resources, uri)
+	if len(ss.resources) == 0 {
+		ss.cancel()
+		delete(m.sessions, sessionID)
+	}
+}
+
+// resources returns a copy of the resources subscribed by the session.
+func (m *subscriptionManager) resources(sessionID string) []watchedResource {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ss, ok := m.sessions[sessionID]
+	if !ok {
+		return nil
+	}
+	resources := make([]watchedResource, 0, len(ss.resources))
+	for _, r := range ss.resources {
+		resources = append(resources, *r)
+	}
+	return resources
+}
+
+// update saves the state of the subscribed resource and reports whether it has changed.
+func (m *subscriptionManager) update(sessionID, uri string, state resourceState) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ss, ok := m.sessions[sessionID]
+	if !ok {
+		return false
+	}
+	r, ok := ss.resources[uri]
+	if !ok || r.state == state {
+		return false
+	}
+	r.state = state
+	return true
+}
+
+// HandleSubscriptionMessage handles the resources/subscribe and resources/unsubscribe requests of the session.
+// It returns false for the other messages, which should be handled by MCPServer.HandleMessage.
+func (s *Server) HandleSubscriptionMessage(ctx context.Context, sessionID string, message json.RawMessage) (mcp.JSONRPCMessage, bool) {
+	var request struct {
+		ID     *mcp.RequestId `json:"id"`
+		Method string         `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil || request.ID == nil {
+		return nil, false
+	}
+	switch request.Method {
+	case methodResourcesSubscribe:
+		if err := s.subscribeResource(ctx, sessionID, request.Params.URI); err != nil {
+			return mcp.NewJSONRPCError(*request.ID, mcp.INVALID_PARAMS, err.Error(), nil), true
+		}
+	case methodResourcesUnsubscribe:
+		s.subscriptions.unsubscribe(sessionID, request.Params.URI)
+	default:
+		return nil, false
+	}
+	return mcp.NewJSONRPCResponse(*request.ID, mcp.Result{}), true
+}
+
+// ServeSubscriptionHTTP handles the resources/subscribe and resources/unsubscribe requests posted to the Streamable-HTTP endpoint.
+// It returns false without writing the response for the other requests, which should be handled by the StreamableHTTPServer.
+// The notifications are sent to the GET stream of the session, so the client must open it before subscribing.
+func (s *Server) ServeSubscriptionHTTP(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	body, err := helper.ReadAndRestoreRequestBody(r)
+	if err != nil {
+		return false
+	}
+	response, ok := s.HandleSubscriptionMessage(r.Context(), r.Header.Get(mcpSessionIDHeader), body)
+	if !ok {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to write subscription response", slog.String("error", err.Error()))
+	}
+	return true
+}
+
+func (s *Server) subscribeResource(ctx context.Context, sessionID, uri string) error {
+	r, err := parseWatchedResourceURI(uri)
+	if err != nil {
+		return err
+	}
+	riskenClient, err := s.GetRISKENClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get RISKEN client: %w", err)
+	}
+	if _, err := s.getURIProject(ctx, riskenClient, uint64(r.projectID)); err != nil {
+		return err
+	}
+	r.riskenClient = riskenClient
+	r.state, err = getResourceState(ctx, r, newAlertListCache())
+	if err != nil {
+		s.invalidateOnAuthError(ctx, riskenClient, err)
+		return fmt.Errorf("failed to get %s", r.kind)
+	}
+
+	watchCtx, err := s.subscriptions.subscribe(sessionID, r)
+	if err != nil {
+		return err
+	}
+	if watchCtx != nil {
+		go s.watchSubscriptions(watchCtx, sessionID)
+	}
+	return nil
+}
+
+// watchSubscriptions polls the resources subscribed by the session until the context is canceled.
+func (s *Server) watchSubscriptions(ctx context.Context, sessionID string) {
+	ticker := time.NewTicker(s.config.ResourcePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.pollSubscriptions(ctx, sessionID)
+		}
+	}
+}
+
+// pollSubscriptions sends notifications/resources/updated to the session for each subscribed resource that has changed.
+// The alerts are listed once for each project and status in a poll, and the subscribed alerts are looked up from them.
+func (s *Server) pollSubscriptions(ctx context.Context, sessionID string) {
+	alerts := newAlertListCache()
+	for _, r := range s.subscriptions.resources(sessionID) {
+		if ctx.Err() != nil {
+			return
+		}
+		state, err := getResourceState(ctx, &r, alerts)
+		if err != nil {
+			if helper.IsAuthError(err) {
+				// The token is no longer valid, so the resource cannot be watched anymore.
+				s.subscriptions.unsubscribe(sessionID, r.uri)
+			}
+			s.logger.Warn("Failed to poll subscribed resource",
+				slog.String("session_id", sessionID),
+				slog.String("uri", r.uri),
+				slog.String("error", err.Error()))
+			continue
+		}
+		if !s.subscriptions.update(sessionID, r.uri, state) {
+			continue
+		}
+		if err := s.subscriptions.notify(sessionID, r.uri); err != nil {
+			s.logger.Warn("Failed to notify resource update",
+				slog.String("session_id", sessionID),
+				slog.String("uri", r.uri),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// parseWatchedResourceURI parses finding://{project_id}/{finding_id} or alert://{project_id}/{alert_id}.
+func parseWatchedResourceURI(uri string) (*watchedResource, error) {
+	scheme, path, ok := strings.Cut(uri, "://")
+	kind := watchedResourceKind(scheme)
+	if !ok || (kind != watchedFinding && kind != watchedAlert) {
+		return nil, fmt.Errorf("unsupported resource URI: %q. Only finding://{project_id}/{finding_id} and alert://{project_id}/{alert_id} can be subscribed", uri)
+	}
+	projectIDValue, idValue, ok := strings.Cut(path, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid resource URI: %q", uri)
+	}
+	projectID, err := strconv.ParseUint(projectIDValue, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %s", projectIDValue)
+	}
+	idBits := 64
+	if kind == watchedAlert {
+		idBits = 32
+	}
+	id, err := strconv.ParseUint(idValue, 10, idBits)
+	if err != nil || id == 0 {
+		return nil, fmt.Errorf("invalid %s_id: %s", kind, idValue)
+	}
+	return &watchedResource{
+		uri:       uri,
+		kind:      kind,
+		projectID: uint32(projectID),
+		id:        id,
+	}, nil
+}
+
+// getResourceState gets the score and pend state of the finding, or the status of the alert looked up from alerts.
+func getResourceState(ctx context.Context, r *watchedResource, alerts *alertListCache) (resourceState, error) {
+	switch r.kind {
+	case watchedFinding:
+		f, err := getFinding(ctx, r.riskenClient, r.projectID, r.id)
+		if err != nil {
+			return resourceState{}, err
+		}
+		pend, err := r.riskenClient.GetPendFinding(ctx, &finding.GetPendFindingRequest{
+			ProjectId: r.projectID,
+			FindingId: r.id,
+		})
+		if err != nil {
+			return resourceState{}, err
+		}
+		return resourceState{
+			Score:   f.Score,
+			Pending: pend != nil && pend.PendFinding != nil && pend.PendFinding.FindingId != 0,
+		}, nil
+	case watchedAlert:
+		a, err := alerts.find(ctx, r.riskenClient, r.projectID, uint32(r.id))
+		if err != nil {
+			return resourceState{}, err
+		}
+		return resourceState{
+			Status:  a.Status.String(),
+			Pending: a.Status == alert.Status_PENDING,
+		}, nil
+	default:
+		return resourceState{}, errors.New("unsupported resource")
+	}
+}
@@ -0,0 +1,238 @@
+package riskenmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type testClientSession struct {
+	id string
+}
+
+func (s *testClientSession) Initialize()                                         {}
+func (s *testClientSession) Initialized() bool                                   { return true }
+func (s *testClientSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *testClientSession) SessionID() string                                   { return s.id }
+
+func TestParseWatchedResourceURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    *watchedResource
+		wantErr string
+	}{
+		{
+			name: "finding",
+			uri:  "finding://1/10",
+			want: &watchedResource{uri: "finding://1/10", kind: watchedFinding, projectID: 1, id: 10},
+		},
+		{
+			name: "alert",
+			uri:  "alert://1/5",
+			want: &watchedResource{uri: "alert://1/5", kind: watchedAlert, projectID: 1, id: 5},
+		},
+		{
+			name:    "unsupported scheme",
+			uri:     "project://1",
+			wantErr: "unsupported resource URI",
+		},
+		{
+			name:    "no id",
+			uri:     "finding://1",
+			wantErr: "invalid resource URI",
+		},
+		{
+			name:    "invalid project_id",
+			uri:     "alert://abc/5",
+			wantErr: "invalid project_id: abc",
+		},
+		{
+			name:    "invalid alert_id",
+			uri:     "alert://1/4294967296",
+			wantErr: "invalid alert_id: 4294967296",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseWatchedResourceURI(tt.uri)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseWatchedResourceURI() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseWatchedResourceURI() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(watchedResource{}, resourceState{})); diff != "" {
+				t.Errorf("parseWatchedResourceURI() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPollSubscriptions(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		score       = "0.5"
+		pend        = `{}`
+		alertStatus = "1"
+		alertLists  int
+		signinCount int32
+	)
+	projectHandler := newTestProjectHandler(&signinCount)
+	client := newTestRISKENClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/finding/get-finding":
+			_, _ = w.Write([]byte(`{"data":{"finding":{"finding_id":10,"project_id":1,"score":` + score + `}}}`))
+		case "/api/v1/finding/get-pend-finding":
+			_, _ = w.Write([]byte(`{"data":{"pend_finding":` + pend + `}}`))
+		case "/api/v1/alert/list-alert":
+			alertLists++
+			if r.URL.Query().Get("status") == alertStatus {
+				_, _ = w.Write([]byte(`{"data":{"alert":[{"alert_id":5,"status":` + alertStatus + `},{"alert_id":6,"status":` + alertStatus + `}]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"alert":[]}}`))
+		default:
+			projectHandler(w, r)
+		}
+	})
+	s := newTestServer(client, nil)
+	var notified []string
+	s.subscriptions = newSubscriptionManager(func(sessionID, uri string) error {
+		notified = append(notified, sessionID+" "+uri)
+		return nil
+	})
+	ctx := context.Background()
+	session := &testClientSession{id: "session-1"}
+
+	subscribe := func(uri string) *mcp.JSONRPCError {
+		t.Helper()
+		message := `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"` + uri + `"}}`
+		response, ok := s.HandleSubscriptionMessage(ctx, session.id, json.RawMessage(message))
+		if !ok {
+			t.Fatalf("HandleSubscriptionMessage(%s) was not handled", uri)
+		}
+		if e, ok := response.(mcp.JSONRPCError); ok {
+			return &e
+		}
+		return nil
+	}
+
+	// the session must have a stream to receive notifications
+	if e := subscribe("finding://1/10"); e == nil || !strings.Contains(e.Error.Message, "no open stream") {
+		t.Fatalf("subscribe() error = %v, want no open stream", e)
+	}
+	s.subscriptions.registerSession(ctx, session)
+	if e := subscribe("finding://999/10"); e == nil || !strings.Contains(e.Error.Message, "not the authenticated project") {
+		t.Fatalf("subscribe() error = %v, want project mismatch", e)
+	}
+	for _, uri := range []string{"finding://1/10", "alert://1/5", "alert://1/6"} {
+		if e := subscribe(uri); e != nil {
+			t.Fatalf("subscribe(%s) error = %v", uri, e.Error.Message)
+		}
+	}
+
+	// no change
+	mu.Lock()
+	alertLists = 0
+	mu.Unlock()
+	s.pollSubscriptions(ctx, session.id)
+	if len(notified) != 0 {
+		t.Errorf("notified = %v, want none", notified)
+	}
+	// the subscribed alerts are looked up from the same list
+	mu.Lock()
+	if alertLists != 1 {
+		t.Errorf("ListAlert calls = %d, want 1", alertLists)
+	}
+	mu.Unlock()
+
+	// score changed
+	mu.Lock()
+	score = "0.9"
+	mu.Unlock()
+	s.pollSubscriptions(ctx, session.id)
+	if diff := cmp.Diff([]string{"session-1 finding://1/10"}, notified); diff != "" {
+		t.Errorf("notified mismatch (-want +got):\n%s", diff)
+	}
+
+	// finding pended and alert status changed
+	notified = nil
+	mu.Lock()
+	pend = `{"finding_id":10,"project_id":1}`
+	alertStatus = "2"
+	alertLists = 0
+	mu.Unlock()
+	s.pollSubscriptions(ctx, session.id)
+	if len(notified) != 3 {
+		t.Errorf("notified = %v, want finding and alerts", notified)
+	}
+	mu.Lock()
+	if alertLists != 2 {
+		t.Errorf("ListAlert calls = %d, want 2", alertLists)
+	}
+	mu.Unlock()
+
+	// unsubscribed resources are not polled
+	notified = nil
+	if _, ok := s.HandleSubscriptionMessage(ctx, session.id, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"resources/unsubscribe","params":{"uri":"alert://1/5"}}`)); !ok {
+		t.Fatal("HandleSubscriptionMessage(unsubscribe) was not handled")
+	}
+	if _, ok := s.HandleSubscriptionMessage(ctx, session.id, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"resources/unsubscribe","params":{"uri":"alert://1/6"}}`)); !ok {
+		t.Fatal("HandleSubscriptionMessage(unsubscribe) was not handled")
+	}
+	mu.Lock()
+	alertStatus = "3"
+	mu.Unlock()
+	s.pollSubscriptions(ctx, session.id)
+	if len(notified) != 0 {
+		t.Errorf("notified = %v, want none", notified)
+	}
+
+	// polling stops when the session closes
+	s.subscriptions.unregisterSession(ctx, session)
+	if got := s.subscriptions.resources(session.id); len(got) != 0 {
+		t.Errorf("resources() = %v, want none", got)
+	}
+
+	// the other messages are not handled
+	if _, ok := s.HandleSubscriptionMessage(ctx, session.id, json.RawMessage(`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"finding://1/10"}}`)); ok {
+		t.Error("HandleSubscriptionMessage(resources/read) was handled")
+	}
+}
+
+func TestFilterSubscriptionMessages(t *testing.T) {
+	s := newTestServer(nil, nil)
+	s.MCPServer = server.NewMCPServer("test", "0.0.1")
+	s.subscriptions = newSubscriptionManager(func(sessionID, uri string) error { return nil })
+	session := &testClientSession{id: "stdio"}
+	ctx := s.MCPServer.WithContext(context.Background(), session)
+
+	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"resources/unsubscribe","params":{"uri":"finding://1/10"}}` + "\n")
+	var next, stdout bytes.Buffer
+	if err := s.filterSubscriptionMessages(ctx, stdin, &next, &stdout); err != nil {
+		t.Fatalf("filterSubscriptionMessages() error = %v", err)
+	}
+	if diff := cmp.Diff(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`+"\n", next.String()); diff != "" {
+		t.Errorf("passed messages mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(`{"jsonrpc":"2.0","id":2,"result":{}}`+"\n", stdout.String()); diff != "" {
+		t.Errorf("stdout mismatch (-want +got):\n%s", diff)
+	}
+}
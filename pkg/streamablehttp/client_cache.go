@@ -0,0 +1,80 @@
+package streamablehttp
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+)
+
+const (
+	defaultClientCacheTTL       = 5 * time.Minute
+	defaultClientCacheSize      = 1000
+	defaultInvalidTokenCacheTTL = 30 * time.Second
+)
+
+// AuthServerOption configures the AuthServer
+type AuthServerOption func(*AuthServer)
+
+// WithClientCacheTTL sets how long a validated RISKEN client is reused for the same token
+func WithClientCacheTTL(ttl time.Duration) AuthServerOption {
+	return func(a *AuthServer) {
+		if ttl > 0 {
+			a.clientCacheTTL = ttl
+		}
+	}
+}
+
+// WithClientCacheSize sets the maximum number of cached RISKEN clients
+func WithClientCacheSize(size int) AuthServerOption {
+	return func(a *AuthServer) {
+		if size > 0 {
+			a.clientCacheSize = size
+		}
+	}
+}
+
+// WithInvalidTokenCacheTTL sets how long a rejected token is remembered as invalid
+func WithInvalidTokenCacheTTL(ttl time.Duration) AuthServerOption {
+	return func(a *AuthServer) {
+		if ttl > 0 {
+			a.invalidTokenCacheTTL = ttl
+		}
+	}
+}
+
+// cachedRISKENClient is a validated client, or the validation error for an invalid token
+type cachedRISKENClient struct {
+	client *risken.Client
+	err    error
+}
+
+// getRISKENClient returns a validated RISKEN client for the token, reusing cached clients per token hash
+func (a *AuthServer) getRISKENClient(ctx context.Context, tokenHash, token string) (*risken.Client, error) {
+	if cached, ok := a.clientCache.Get(tokenHash); ok {
+		return cached.client, cached.err
+	}
+
+	client, err := helper.CreateAndValidateRISKENClient(ctx, a.riskenURL, token)
+	if err != nil {
+		if helper.IsAuthError(err) {
+			a.clientCache.SetWithTTL(tokenHash, &cachedRISKENClient{err: err}, a.invalidTokenCacheTTL)
+		}
+		return nil, err
+	}
+	a.clientCache.Set(tokenHash, &cachedRISKENClient{client: client})
+
+	hits, misses := a.ClientCacheStats()
+	a.logger.Debug("Cached RISKEN client",
+		slog.Uint64("cache_hits", hits),
+		slog.Uint64("cache_misses", misses),
+		slog.Int("cache_size", a.clientCache.Len()))
+	return client, nil
+}
+
+// ClientCacheStats returns the hit and miss counters of the RISKEN client cache
+func (a *AuthServer) ClientCacheStats() (hits, misses uint64) {
+	return a.clientCache.Stats()
+}
@@ -0,0 +1,69 @@
+package streamablehttp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ca-risken/go-risken"
+	"github.com/ca-risken/risken-mcp-server/pkg/helper"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestGetRISKENClient(t *testing.T) {
+	var signinCount int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&signinCount, 1)
+		if r.Header.Get("Authorization") != "Bearer valid-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"project_id":1}`))
+	}))
+	defer ts.Close()
+
+	a := NewAuthServer(
+		server.NewMCPServer("test", "0.0.1"),
+		ts.URL,
+		"/mcp",
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	ctx := context.Background()
+
+	// valid token is validated once and reused
+	var first *risken.Client
+	for i := range 3 {
+		client, err := a.getRISKENClient(ctx, helper.HashToken("valid-token"), "valid-token")
+		if err != nil {
+			t.Fatalf("getRISKENClient() error = %v", err)
+		}
+		if i == 0 {
+			first = client
+		} else if client != first {
+			t.Error("getRISKENClient() should return the cached client")
+		}
+	}
+	if signinCount != 1 {
+		t.Errorf("signin count = %d, want 1", signinCount)
+	}
+
+	// invalid token is negatively cached
+	for range 2 {
+		if _, err := a.getRISKENClient(ctx, helper.HashToken("invalid-token"), "invalid-token"); err == nil {
+			t.Error("getRISKENClient() error = nil, want error")
+		}
+	}
+	if signinCount != 2 {
+		t.Errorf("signin count = %d, want 2", signinCount)
+	}
+
+	hits, misses := a.ClientCacheStats()
+	if hits != 3 || misses != 2 {
+		t.Errorf("ClientCacheStats() = %d, %d, want 3, 2", hits, misses)
+	}
+}
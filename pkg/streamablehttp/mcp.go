@@ -8,6 +8,14 @@ import (
 	"github.com/ca-risken/risken-mcp-server/pkg/riskenmcp"
 )
 
+// WithSubscriptionHandler sets the handler of resources/subscribe and resources/unsubscribe requests.
+// The handler returns false for the other requests, which are delegated to the StreamableHTTPServer.
+func WithSubscriptionHandler(handler func(http.ResponseWriter, *http.Request) bool) AuthServerOption {
+	return func(a *AuthServer) {
+		a.subscriptionHandler = handler
+	}
+}
+
 // ServeHTTP handles MCP requests(/mcp) with RISKEN token validation
 func (a *AuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Extract requestID from JSON-RPC
@@ -26,8 +34,9 @@ func (a *AuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify token
-	riskenClient, err := helper.CreateAndValidateRISKENClient(r.Context(), a.riskenURL, riskenToken)
+	// Verify token (validated clients are cached per token hash)
+	tokenHash := helper.HashToken(riskenToken)
+	riskenClient, err := a.getRISKENClient(r.Context(), tokenHash, riskenToken)
 	if err != nil {
 		jsonRPCError := riskenmcp.NewJSONRPCError(requestID, riskenmcp.JSONRPCErrorUnauthorized, fmt.Sprintf("Invalid RISKEN token: %s", err))
 		http.Error(w, jsonRPCError.String(), http.StatusUnauthorized)
@@ -36,8 +45,14 @@ func (a *AuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Add RISKEN Client to the request context
 	ctx := riskenmcp.WithRISKENClient(r.Context(), riskenClient)
+	ctx = riskenmcp.WithRISKENClientKey(ctx, tokenHash)
 	r = r.WithContext(ctx)
 
+	// Handle resource subscriptions
+	if a.subscriptionHandler != nil && a.subscriptionHandler(w, r) {
+		return
+	}
+
 	// Delegate to the original handler
 	a.StreamableHTTPServer.ServeHTTP(w, r)
 }
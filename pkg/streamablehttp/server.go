@@ -19,16 +19,33 @@ type AuthServer struct {
 	logger       *slog.Logger
 	httpServer   *http.Server
 	mu           sync.RWMutex
+
+	// Cache of validated RISKEN clients per token hash
+	clientCache          *helper.TTLCache[*cachedRISKENClient]
+	clientCacheTTL       time.Duration
+	clientCacheSize      int
+	invalidTokenCacheTTL time.Duration
+
+	// Handler of the resource subscription requests, which the StreamableHTTPServer does not support
+	subscriptionHandler func(http.ResponseWriter, *http.Request) bool
 }
 
 // NewAuthServer creates a new authenticated server instance
-func NewAuthServer(mcpServer *server.MCPServer, riskenURL, endpointPath string, logger *slog.Logger) *AuthServer {
-	return &AuthServer{
+func NewAuthServer(mcpServer *server.MCPServer, riskenURL, endpointPath string, logger *slog.Logger, opts ...AuthServerOption) *AuthServer {
+	a := &AuthServer{
 		StreamableHTTPServer: server.NewStreamableHTTPServer(mcpServer, server.WithEndpointPath(endpointPath)),
 		endpointPath:         endpointPath,
 		riskenURL:            riskenURL,
 		logger:               logger,
+		clientCacheTTL:       defaultClientCacheTTL,
+		clientCacheSize:      defaultClientCacheSize,
+		invalidTokenCacheTTL: defaultInvalidTokenCacheTTL,
+	}
+	for _, opt := range opts {
+		opt(a)
 	}
+	a.clientCache = helper.NewTTLCache[*cachedRISKENClient](a.clientCacheTTL, a.clientCacheSize)
+	return a
 }
 
 // Override Start method to apply authentication